@@ -0,0 +1,139 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+/*
+#include <pbc/pbc.h>
+*/
+import "C"
+
+import "runtime"
+
+// Arena allocates elements of a pairing from reusable native storage. It is
+// intended for protocols that create many short-lived elements, such as
+// verifiers that run the same computation repeatedly: elements obtained from
+// an arena have no finalizers, and elements returned with Put are reset and
+// handed out again without calling into the C allocator. Like the elements
+// created by the pairing, elements obtained from an arena are set to zero.
+//
+// An arena owns the native memory of all of its elements, which is freed at
+// once by Release. Elements obtained from an arena are checked elements, and
+// they panic with ErrClosed if they are used after being returned with Put or
+// after the arena is released. Elements derived from them using
+// NewFieldElement are ordinary elements that are not owned by the arena.
+//
+// Arenas are not safe for concurrent use; each goroutine should use its own
+// arena. If an arena becomes unreachable before Release is called, its memory
+// is freed by the garbage collector once none of its elements are reachable.
+type Arena struct {
+	pairing  *Pairing
+	free     [Zr + 1][]*C.struct_element_s
+	all      []arenaItem
+	released bool
+}
+
+type arenaItem struct {
+	cptr  *C.struct_element_s
+	field Field
+}
+
+// NewArena creates a new, empty arena for elements of the pairing. The pairing
+// cannot be freed until the arena is released.
+func (pairing *Pairing) NewArena() *Arena {
+	pairing.retain()
+	arena := &Arena{pairing: pairing}
+	runtime.SetFinalizer(arena, (*Arena).Release)
+	return arena
+}
+
+// NewG1 returns a checked element of G1 from the arena.
+func (arena *Arena) NewG1() *Element { return arena.get(G1) }
+
+// NewG2 returns a checked element of G2 from the arena.
+func (arena *Arena) NewG2() *Element { return arena.get(G2) }
+
+// NewGT returns a checked element of GT from the arena.
+func (arena *Arena) NewGT() *Element { return arena.get(GT) }
+
+// NewZr returns a checked element of Zr from the arena.
+func (arena *Arena) NewZr() *Element { return arena.get(Zr) }
+
+func (arena *Arena) get(field Field) *Element {
+	if arena.released {
+		panic(ErrClosed)
+	}
+	fieldPtr := arena.pairing.fieldPtr(field)
+	var cptr *C.struct_element_s
+	if n := len(arena.free[field]); n > 0 {
+		cptr = arena.free[field][n-1]
+		arena.free[field] = arena.free[field][:n-1]
+	} else {
+		// The arena holds the only reference to the native element, which is
+		// freed by Release rather than by a finalizer.
+		el := makeCheckedElement(arena.pairing, field, fieldPtr)
+		detachElement(el)
+		cptr = el.cptr
+		arena.all = append(arena.all, arenaItem{cptr: cptr, field: field})
+	}
+	return &Element{
+		pairing:   arena.pairing,
+		arena:     arena,
+		cptr:      cptr,
+		field:     field,
+		checked:   true,
+		fieldPtr:  fieldPtr,
+		isInteger: field == Zr,
+	}
+}
+
+// Put returns el to the arena so that its storage can be reused. The value of
+// el is reset, and el must not be used afterwards; elements returned by later
+// calls to the arena may share its storage.
+//
+// Requirements:
+// el must have been obtained from this arena and not already returned.
+func (arena *Arena) Put(el *Element) {
+	if el.arena != arena || el.parent != nil {
+		panic(ErrIllegalOp)
+	}
+	el.ensureOpen()
+	C.element_set0(el.cptr)
+	arena.free[el.field] = append(arena.free[el.field], el.cptr)
+	el.cptr = nil
+}
+
+// Release frees the native memory of all elements obtained from the arena,
+// including those that have not been returned with Put. The elements and the
+// arena must not be used afterwards. Release is idempotent.
+func (arena *Arena) Release() {
+	if arena.released {
+		return
+	}
+	arena.released = true
+	runtime.SetFinalizer(arena, nil)
+	for _, item := range arena.all {
+		freeDetachedElement(&Element{cptr: item.cptr, field: item.field})
+	}
+	arena.all = nil
+	arena.free = [Zr + 1][]*C.struct_element_s{}
+	arena.pairing.release()
+}
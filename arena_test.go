@@ -0,0 +1,59 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import "testing"
+
+func TestArena(t *testing.T) {
+	pairing := testPairing(t)
+	arena := pairing.NewArena()
+
+	g := pairing.NewG1().Rand()
+	before := Stats()
+	for i := 0; i < 10; i++ {
+		x := arena.NewZr().Rand()
+		h := arena.NewG1().PowZn(g, x)
+		expected := pairing.NewG1().PowZn(g, x)
+		if !h.Equals(expected) {
+			t.Fatal("arena element computed the wrong result")
+		}
+		arena.Put(x)
+		h.Close()
+	}
+	after := Stats()
+	if n := after.Elements[Zr].Allocs - before.Elements[Zr].Allocs; n != 1 {
+		t.Errorf("arena storage was not reused: %d allocations", n)
+	}
+
+	x := arena.NewZr()
+	if !x.Is0() {
+		t.Error("reused element was not reset")
+	}
+
+	expectPanic(t, ErrIllegalOp, func() { arena.Put(pairing.NewZr()) })
+
+	arena.Release()
+	arena.Release()
+	expectPanic(t, ErrClosed, func() { x.Rand() })
+	expectPanic(t, ErrClosed, func() { arena.NewG1() })
+	x.Close()
+}
@@ -0,0 +1,186 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// These tests are most useful when run with the race detector enabled.
+
+func TestConcurrentOperations(t *testing.T) {
+	pairing := testPairing(t)
+	g := pairing.NewG1().Rand()
+	h := pairing.NewG2().Rand()
+	expected := pairing.NewGT().Pair(g, h)
+	power := g.PreparePower()
+	pairer := g.PreparePairer()
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 64)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				x := pairing.NewZr().Rand()
+				if !pairing.NewGT().Pair(g, h).Equals(expected) {
+					errs <- "concurrent pairings disagree"
+					return
+				}
+				if !pairer.Pair(pairing.NewGT(), h).Equals(expected) {
+					errs <- "concurrent pairer disagrees"
+					return
+				}
+				if !power.PowZn(pairing.NewG1(), x).Equals(pairing.NewG1().PowZn(g, x)) {
+					errs <- "concurrent power disagrees"
+					return
+				}
+				g.PreparePower().Close()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestConcurrentRandomProvider(t *testing.T) {
+	defer SetDefaultRandom()
+	pairing := testPairing(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				pairing.NewZr().Rand()
+				pairing.NewG1().Rand()
+			}
+		}()
+		go func(seed int64) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				SetRandRandom(rand.New(rand.NewSource(seed)))
+				SetDefaultRandom()
+				SetLogging(Logging())
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+}
+
+// blockingSource signals started on its first draw and then waits for
+// release before drawing from math/rand.
+type blockingSource struct {
+	once             sync.Once
+	started, release chan struct{}
+}
+
+func (src *blockingSource) Rand(limit *big.Int) *big.Int {
+	src.once.Do(func() { close(src.started) })
+	<-src.release
+	return new(big.Int).Rand(rand.New(rand.NewSource(1)), limit)
+}
+
+func TestGenerationDoesNotBlockRandomProvider(t *testing.T) {
+	defer SetDefaultRandom()
+	src := &blockingSource{started: make(chan struct{}), release: make(chan struct{})}
+	SetRandomProvider(src)
+	done := make(chan *Params)
+	go func() { done <- GenerateA(10, 32) }()
+	<-src.started
+
+	// Neither call may wait for the generation to finish
+	SetDefaultRandom()
+	testPairing(t).NewG1().Rand()
+
+	close(src.release)
+	if params := <-done; params.Type() != TypeA {
+		t.Errorf("generated parameters of type %s", params.Type())
+	}
+}
+
+func TestRandFromSource(t *testing.T) {
+	pairing := testPairing(t)
+	sample := func(seed int64) []*Element {
+		src := &randProvider{source: rand.New(rand.NewSource(seed))}
+		return []*Element{
+			pairing.NewG1().RandFromSource(src),
+			pairing.NewG2().RandFromSource(src),
+			pairing.NewGT().RandFromSource(src),
+			pairing.NewZr().RandFromSource(src),
+			pairing.NewG1().Rand().Item(0).NewFieldElement().RandFromSource(src),
+		}
+	}
+
+	// Sampling is deterministic for a given source, and independent of the
+	// global source
+	SetRandRandom(rand.New(rand.NewSource(1)))
+	a := sample(42)
+	SetDefaultRandom()
+	b := sample(42)
+	c := sample(43)
+	r := pairing.Order()
+	for i := range a {
+		if !a[i].Equals(b[i]) {
+			t.Errorf("element %d differs for the same source", i)
+		}
+		if a[i].Equals(c[i]) {
+			t.Errorf("element %d is the same for different sources", i)
+		}
+	}
+	for i, el := range a[:3] {
+		if !el.NewFieldElement().PowBig(el, r).Is1() {
+			t.Errorf("element %d is not in the subgroup of order r", i)
+		}
+	}
+	if x := a[3].BigInt(); x.Sign() < 0 || x.Cmp(r) >= 0 {
+		t.Error("Zr element out of range")
+	}
+	for i := range pairing.curveMaps {
+		if pairing.curveMaps[i].m != nil {
+			t.Error("sampling prepared hashing to the curve")
+		}
+	}
+
+	// Concurrent sampling with per-goroutine sources
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			src := &randProvider{source: rand.New(rand.NewSource(seed))}
+			for j := 0; j < 20; j++ {
+				pairing.NewG1().RandFromSource(src)
+				pairing.NewGT().RandFromSource(src)
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+}
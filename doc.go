@@ -66,6 +66,24 @@
 	for the corresponding generator calls, or the PBC manual page at
 	https://crypto.stanford.edu/pbc/manual/ch05s01.html.
 
+	Concurrency
+
+	Pairings and Params are immutable once created, and are safe for concurrent
+	use by multiple goroutines. Elements, Powers, and Pairers follow the usual
+	rules for Go values: any number of goroutines may read them concurrently,
+	for example by using the same element as an operand or by using the same
+	Power or Pairer, but an element must not be modified while other goroutines
+	are accessing it. Distinct elements of the same pairing can be used in
+	parallel. Arenas are not safe for concurrent use.
+
+	By default, random values are drawn from a single global source, which can
+	be replaced with SetRandomProvider at any time. The source is looked up for
+	each random number, so operations that are running while it is replaced,
+	such as a long parameter generation, may draw from both the old and the new
+	source; replacing the source never waits for them. A RandomSource installed
+	globally must be safe for concurrent use. To use a different source in each
+	goroutine, or to avoid the global source altogether, use RandFromSource.
+
 	Dependencies
 
 	This package must be compiled using cgo. It also requires the installation
@@ -0,0 +1,183 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"math/big"
+	"sync"
+)
+
+const (
+	// drbgMinSeedLength is the minimum seed length for a security strength of
+	// 128 bits.
+	drbgMinSeedLength = 16
+
+	// drbgMaxRequest is the maximum number of bytes produced by a single
+	// generate request (SP 800-90A, table 2).
+	drbgMaxRequest = 1 << 16
+
+	// drbgReseedInterval is the maximum number of generate requests between
+	// reseeds (SP 800-90A, table 2).
+	drbgReseedInterval = 1 << 48
+)
+
+// DRBG is a deterministic random bit generator implementing HMAC_DRBG from
+// NIST SP 800-90A with SHA-256. Its output is completely determined by its
+// seed, personalization string, and the sequence of calls made to it, which
+// makes it suitable for reproducible test vectors and protocol transcripts.
+// When the seed is secret and contains enough entropy, the output is
+// cryptographically secure, so it can also be used to derive deterministic
+// nonces, in the style of RFC 6979:
+//
+// 	seed := append(secretKey.Bytes(), messageHash...)
+// 	drbg, err := pbc.NewDRBG(seed, []byte("my-protocol signature nonce"))
+// 	k := pairing.NewZr().RandFromSource(drbg)
+//
+// DRBG implements RandomSource and io.Reader. It is safe for concurrent use,
+// but the output is only reproducible if the order of calls is.
+type DRBG struct {
+	mu            sync.Mutex
+	k             []byte
+	v             []byte
+	reseedCounter uint64
+}
+
+// NewDRBG instantiates a new DRBG. The seed is the entropy input, optionally
+// followed by a nonce, and must be at least 16 bytes long; it should contain
+// at least 128 bits of entropy if the output must be unpredictable. The
+// personalization string, which may be empty, separates the outputs of
+// generators instantiated with the same seed.
+func NewDRBG(seed, personalization []byte) (*DRBG, error) {
+	if len(seed) < drbgMinSeedLength {
+		return nil, ErrShortSeed
+	}
+	drbg := &DRBG{
+		k: make([]byte, sha256.Size),
+		v: make([]byte, sha256.Size),
+	}
+	for i := range drbg.v {
+		drbg.v[i] = 0x01
+	}
+	drbg.update(seed, personalization)
+	drbg.reseedCounter = 1
+	return drbg, nil
+}
+
+// update implements HMAC_DRBG_Update. The provided data is concatenated.
+func (drbg *DRBG) update(data ...[]byte) {
+	empty := true
+	for _, d := range data {
+		empty = empty && len(d) == 0
+	}
+	for _, round := range []byte{0x00, 0x01} {
+		if round == 0x01 && empty {
+			return
+		}
+		h := hmac.New(sha256.New, drbg.k)
+		h.Write(drbg.v)
+		h.Write([]byte{round})
+		for _, d := range data {
+			h.Write(d)
+		}
+		drbg.k = h.Sum(drbg.k[:0])
+		h = hmac.New(sha256.New, drbg.k)
+		h.Write(drbg.v)
+		drbg.v = h.Sum(drbg.v[:0])
+	}
+}
+
+// Reseed mixes fresh entropy and optional additional input into the state of
+// the generator. The entropy must be at least 16 bytes long.
+func (drbg *DRBG) Reseed(entropy, additional []byte) error {
+	if len(entropy) < drbgMinSeedLength {
+		return ErrShortSeed
+	}
+	drbg.mu.Lock()
+	defer drbg.mu.Unlock()
+	drbg.update(entropy, additional)
+	drbg.reseedCounter = 1
+	return nil
+}
+
+// generate implements HMAC_DRBG_Generate for a request of at most
+// drbgMaxRequest bytes, without additional input.
+func (drbg *DRBG) generate(out []byte) error {
+	if drbg.reseedCounter > drbgReseedInterval {
+		return ErrReseedRequired
+	}
+	h := hmac.New(sha256.New, drbg.k)
+	for len(out) > 0 {
+		h.Reset()
+		h.Write(drbg.v)
+		drbg.v = h.Sum(drbg.v[:0])
+		out = out[copy(out, drbg.v):]
+	}
+	drbg.update()
+	drbg.reseedCounter++
+	return nil
+}
+
+// Read fills p with pseudorandom bytes. Requests larger than 64 KiB are split
+// into several generate requests. Read returns ErrReseedRequired if the
+// generator must be reseeded, which only happens after 2^48 requests.
+func (drbg *DRBG) Read(p []byte) (int, error) {
+	drbg.mu.Lock()
+	defer drbg.mu.Unlock()
+	n := 0
+	for n < len(p) {
+		end := n + drbgMaxRequest
+		if end > len(p) {
+			end = len(p)
+		}
+		if err := drbg.generate(p[n:end]); err != nil {
+			return n, err
+		}
+		n = end
+	}
+	return n, nil
+}
+
+// Rand returns a uniformly random integer in [0,limit), which must be
+// positive. It uses rejection sampling, so the result is unbiased. Rand
+// panics with ErrOutOfRange if limit is not positive, and with
+// ErrEntropyFailure if the generator must be reseeded.
+func (drbg *DRBG) Rand(limit *big.Int) *big.Int {
+	if limit.Sign() <= 0 {
+		panic(ErrOutOfRange)
+	}
+	bits := new(big.Int).Sub(limit, big.NewInt(1)).BitLen()
+	buf := make([]byte, (bits+7)/8)
+	result := new(big.Int)
+	for {
+		if _, err := drbg.Read(buf); err != nil {
+			panic(ErrEntropyFailure)
+		}
+		if len(buf) > 0 {
+			buf[0] &= byte(0xff >> uint(8*len(buf)-bits))
+		}
+		if result.SetBytes(buf).Cmp(limit) < 0 {
+			return result
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestDRBG(t *testing.T) {
+	// NIST CAVP HMAC_DRBG test vector (SHA-256, no prediction resistance, no
+	// reseed, no personalization string, no additional input, COUNT = 0)
+	entropy, _ := hex.DecodeString("ca851911349384bffe89de1cbdc46e6831e44d34a4fb935ee285dd14b71a7488")
+	nonce, _ := hex.DecodeString("659ba96c601dc69fc902940805ec0ca8")
+	expected, _ := hex.DecodeString("e528e9abf2dece54d47c7e75e5fe302149f817ea9fb4bee6f4199697d04d5b89" +
+		"d54fbb978a15b5c443c9ec21036d2460b6f73ebad0dc2aba6e624abf07745bc1" +
+		"07694bb7547bb0995f70de25d6b29e2d3011bb19d27676c07162c8b5ccde0668" +
+		"961df86803482cb37ed6d5c0bb8d50cf1f50d476aa0458bdaba806f48be9dcb8")
+
+	drbg, err := NewDRBG(append(entropy, nonce...), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]byte, len(expected))
+	drbg.Read(out)
+	drbg.Read(out)
+	if !bytes.Equal(out, expected) {
+		t.Fatalf("unexpected output: %x", out)
+	}
+
+	if _, err := NewDRBG(nonce[:15], nil); err != ErrShortSeed {
+		t.Error("short seed was accepted")
+	}
+
+	// Personalization and reseeding change the output
+	a, _ := NewDRBG(entropy, []byte("a"))
+	b, _ := NewDRBG(entropy, []byte("b"))
+	outA, outB := make([]byte, 32), make([]byte, 32)
+	a.Read(outA)
+	b.Read(outB)
+	if bytes.Equal(outA, outB) {
+		t.Error("personalization string was ignored")
+	}
+	b, _ = NewDRBG(entropy, []byte("a"))
+	if err := b.Reseed(nonce, nil); err != nil {
+		t.Fatal(err)
+	}
+	b.Read(outB)
+	a.Read(outA)
+	if bytes.Equal(outA, outB) {
+		t.Error("reseeding was ignored")
+	}
+
+	// Rand is reproducible and in range
+	limit := big.NewInt(641)
+	a, _ = NewDRBG(entropy, nil)
+	b, _ = NewDRBG(entropy, nil)
+	for i := 0; i < 100; i++ {
+		x, y := a.Rand(limit), b.Rand(limit)
+		if x.Cmp(y) != 0 {
+			t.Fatal("Rand is not reproducible")
+		}
+		if x.Sign() < 0 || x.Cmp(limit) >= 0 {
+			t.Fatalf("Rand returned %s, which is out of range", x)
+		}
+	}
+
+	pairing := testPairing(t)
+	a, _ = NewDRBG(entropy, []byte("nonce"))
+	b, _ = NewDRBG(entropy, []byte("nonce"))
+	if !pairing.NewG1().RandFromSource(a).Equals(pairing.NewG1().RandFromSource(b)) {
+		t.Error("seeded sampling is not reproducible")
+	}
+}
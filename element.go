@@ -106,21 +106,98 @@ import "runtime"
 // requested computations make sense.
 type Element struct {
 	pairing *Pairing // Prevents garbage collection
+	parent  *Element // Prevents garbage collection of the owner of cptr
+	arena   *Arena   // Set for elements owned by an arena
+	owner   *Element // Holds the finalizer for cptr if el was decoded in place
 	cptr    *C.struct_element_s
 
+	field     Field
 	checked   bool
 	fieldPtr  *C.struct_field_s
 	isInteger bool
 }
 
+// noField is the Field of elements that do not belong to one of the algebraic
+// structures of a pairing, such as the coordinates returned by Item.
+const noField Field = -1
+
+// Field returns the algebraic structure (G1, G2, GT, or Zr) that el belongs
+// to. Elements returned by Item do not belong to any of these structures; for
+// such elements, Field panics with ErrIllegalOp.
+func (el *Element) Field() Field {
+	if el.field == noField {
+		panic(ErrIllegalOp)
+	}
+	return el.field
+}
+
+// Close frees the native memory used by el. Go does not account for this
+// memory when deciding when to collect garbage, so programs that create many
+// elements should close them explicitly instead of relying on the garbage
+// collector. Close is idempotent. Checked elements panic with ErrClosed if
+// they are used after being closed; using a closed unchecked element results
+// in undefined behavior.
+//
+// Elements returned by Item share memory with their parent element. Closing
+// such an element only detaches it from its parent. Closing an element
+// obtained from an Arena returns it to the arena, as with Arena.Put.
+func (el *Element) Close() {
+	if el.cptr == nil {
+		return
+	}
+	if el.parent == nil && el.arena != nil {
+		if !el.arena.released {
+			el.arena.Put(el)
+		}
+	} else if el.owner != nil {
+		el.owner.Close()
+		el.owner = nil
+	} else if el.parent == nil {
+		runtime.SetFinalizer(el, nil)
+		clearElement(el)
+	}
+	el.cptr = nil
+}
+
 func clearElement(element *Element) {
+	recordFree(ElementObject, element.field, element.nativeSize())
 	C.freeElementStruct(element.cptr)
+	element.pairing.release()
+}
+
+// nativeSize approximates the C heap memory used by el, which must be
+// initialized.
+func (el *Element) nativeSize() int64 {
+	return int64(C.sizeof_struct_element_s) + int64(C.element_length_in_bytes(el.cptr))
+}
+
+// recordAlloc records the allocation of el in the statistics reported by
+// Stats. It is called once el has been initialized.
+func (el *Element) recordAlloc() {
+	recordAlloc(ElementObject, el.field, el.nativeSize())
+}
+
+// detachElement removes the finalizer of x and its reference to the pairing,
+// so that x can be stored in the pairing itself without forming a cycle. The
+// pairing must free x with freeDetachedElement before freeing itself.
+func detachElement(x *Element) {
+	runtime.SetFinalizer(x, nil)
+	x.pairing.release()
+	x.pairing = nil
+}
+
+func freeDetachedElement(x *Element) {
+	recordFree(ElementObject, x.field, x.nativeSize())
+	C.freeElementStruct(x.cptr)
+	x.cptr = nil
 }
 
 func makeUncheckedElement(pairing *Pairing, initialize bool, field Field) *Element {
+	pairing.retain()
 	element := &Element{
 		cptr:    C.newElementStruct(),
 		pairing: pairing,
+		field:   field,
 	}
 	if initialize {
 		switch field {
@@ -135,6 +212,7 @@ func makeUncheckedElement(pairing *Pairing, initialize bool, field Field) *Eleme
 		default:
 			panic(ErrUnknownField)
 		}
+		element.recordAlloc()
 	}
 	runtime.SetFinalizer(element, clearElement)
 	return element
@@ -155,13 +233,21 @@ func checkFieldsMatch(f1, f2 *C.struct_field_s) {
 	}
 }
 
+func (el *Element) ensureOpen() {
+	if el.cptr == nil || (el.arena != nil && el.arena.released) {
+		panic(ErrClosed)
+	}
+}
+
 func (el *Element) ensureChecked() {
 	if !el.checked {
 		panic(ErrUncheckedOp)
 	}
+	el.ensureOpen()
 }
 
 func (el *Element) checkCompatible(other *Element) {
+	el.ensureOpen()
 	other.ensureChecked()
 	checkFieldsMatch(el.fieldPtr, other.fieldPtr)
 }
@@ -47,6 +47,9 @@ import (
 // Set0 sets el to zero and returns el. For curves, this sets the element to
 // the infinite point (identity element).
 func (el *Element) Set0() *Element {
+	if el.checked {
+		el.ensureOpen()
+	}
 	C.element_set0(el.cptr)
 	return el
 }
@@ -54,6 +57,9 @@ func (el *Element) Set0() *Element {
 // Set1 sets el to one and returns el. For curves, this sets the element to the
 // infinite point (identity element).
 func (el *Element) Set1() *Element {
+	if el.checked {
+		el.ensureOpen()
+	}
 	C.element_set1(el.cptr)
 	return el
 }
@@ -61,6 +67,9 @@ func (el *Element) Set1() *Element {
 // Rand sets el to a random value and returns el. For algebraic structures
 // where this does not make sense, this is equivalent to Set0.
 func (el *Element) Rand() *Element {
+	if el.checked {
+		el.ensureOpen()
+	}
 	C.element_random(el.cptr)
 	return el
 }
@@ -78,16 +87,25 @@ func (el *Element) Equals(x *Element) bool {
 
 // Is0 returns true if el is zero (or the identity element for curves).
 func (el *Element) Is0() bool {
+	if el.checked {
+		el.ensureOpen()
+	}
 	return C.element_is0(el.cptr) != 0
 }
 
 // Is1 returns true if el is one (or the identity element for curves).
 func (el *Element) Is1() bool {
+	if el.checked {
+		el.ensureOpen()
+	}
 	return C.element_is1(el.cptr) != 0
 }
 
 // IsSquare returns true if el is a perfect square (quadratic residue).
 func (el *Element) IsSquare() bool {
+	if el.checked {
+		el.ensureOpen()
+	}
 	return C.element_is_sqr(el.cptr) != 0
 }
 
@@ -95,6 +113,9 @@ func (el *Element) IsSquare() bool {
 // algebraic structure, but has the property that el.Sign() == -neg.Sign()
 // where neg is the negation of el.
 func (el *Element) Sign() int {
+	if el.checked {
+		el.ensureOpen()
+	}
 	sign := int64(C.element_sign(el.cptr))
 	if sign > 0 {
 		return 1
@@ -153,7 +174,9 @@ func (el *Element) MulBig(x *Element, i *big.Int) *Element {
 	if el.checked {
 		el.checkCompatible(x)
 	}
-	C.element_mul_mpz(el.cptr, x.cptr, &big2mpz(i).i[0])
+	mi := big2mpz(i)
+	C.element_mul_mpz(el.cptr, x.cptr, &mi.i[0])
+	mi.free()
 	return el
 }
 
@@ -262,6 +285,7 @@ func (el *Element) Invert(x *Element) *Element {
 
 // PowBig sets el = x^i and returns el. More precisely, el = x * x * ... * x
 // where there are i x's. For curve points, * denotes the group operation.
+// PowBig is not constant-time; use PowBigCT if i is secret.
 //
 // Requirements:
 // el and x must be from the same algebraic structure.
@@ -269,12 +293,15 @@ func (el *Element) PowBig(x *Element, i *big.Int) *Element {
 	if el.checked {
 		el.checkCompatible(x)
 	}
-	C.element_pow_mpz(el.cptr, x.cptr, &big2mpz(i).i[0])
+	mi := big2mpz(i)
+	C.element_pow_mpz(el.cptr, x.cptr, &mi.i[0])
+	mi.free()
 	return el
 }
 
 // PowZn sets el = x^i and returns el. More precisely, el = x * x * ... * x
 // where there are i x's. For curve points, * denotes the group operation.
+// PowZn is not constant-time; use PowZnCT if i is secret.
 //
 // Requirements:
 // el and x must be from the same algebraic structure; and
@@ -298,7 +325,10 @@ func (el *Element) Pow2Big(x *Element, i *big.Int, y *Element, j *big.Int) *Elem
 	if el.checked {
 		el.checkAllCompatible(x, y)
 	}
-	C.element_pow2_mpz(el.cptr, x.cptr, &big2mpz(i).i[0], y.cptr, &big2mpz(j).i[0])
+	mi, mj := big2mpz(i), big2mpz(j)
+	C.element_pow2_mpz(el.cptr, x.cptr, &mi.i[0], y.cptr, &mj.i[0])
+	mi.free()
+	mj.free()
 	return el
 }
 
@@ -328,7 +358,11 @@ func (el *Element) Pow3Big(x *Element, i *big.Int, y *Element, j *big.Int, z *El
 	if el.checked {
 		el.checkAllCompatible(x, y, z)
 	}
-	C.element_pow3_mpz(el.cptr, x.cptr, &big2mpz(i).i[0], y.cptr, &big2mpz(j).i[0], z.cptr, &big2mpz(k).i[0])
+	mi, mj, mk := big2mpz(i), big2mpz(j), big2mpz(k)
+	C.element_pow3_mpz(el.cptr, x.cptr, &mi.i[0], y.cptr, &mj.i[0], z.cptr, &mk.i[0])
+	mi.free()
+	mj.free()
+	mk.free()
 	return el
 }
 
@@ -363,6 +397,12 @@ type Power struct {
 // Source returns the Element for which the pre-processed data was generated.
 func (power *Power) Source() *Element { return power.source }
 
+func (power *Power) ensureOpen() {
+	if power.pp == nil {
+		panic(ErrClosed)
+	}
+}
+
 // PowBig sets target = s^i where s was the source Element for the Power, and
 // returns target. It is equivalent to target.PowerBig(power, i).
 //
@@ -381,19 +421,38 @@ func (power *Power) PowZn(target *Element, i *Element) *Element {
 	return target.PowerZn(power, i)
 }
 
+// Close frees the native memory used by the pre-processed data. Close is
+// idempotent. Using a closed Power with a checked target panics with
+// ErrClosed.
+func (power *Power) Close() {
+	if power.pp == nil {
+		return
+	}
+	runtime.SetFinalizer(power, nil)
+	clearPower(power)
+	power.pp = nil
+}
+
 func clearPower(power *Power) {
+	recordFree(PowerObject, noField, int64(C.sizeof_struct_element_pp_s))
 	C.freeElementPPStruct(power.pp)
+	power.source.pairing.release()
 }
 
 // PreparePower generates pre-processing data for repeatedly exponentiating el.
 // The returned Power can be used to raise el to a power several times, and is
 // generally faster than repeatedly calling the standard Pow methods on el.
 func (el *Element) PreparePower() *Power {
+	if el.checked {
+		el.ensureOpen()
+	}
+	el.pairing.retain()
 	power := &Power{
 		source: el,
 		pp:     C.newElementPPStruct(),
 	}
 	C.element_pp_init(power.pp, el.cptr)
+	recordAlloc(PowerObject, noField, int64(C.sizeof_struct_element_pp_s))
 	runtime.SetFinalizer(power, clearPower)
 	return power
 }
@@ -406,8 +465,11 @@ func (el *Element) PreparePower() *Power {
 func (el *Element) PowerBig(power *Power, i *big.Int) *Element {
 	if el.checked {
 		el.checkCompatible(power.source)
+		power.ensureOpen()
 	}
-	C.element_pp_pow(el.cptr, &big2mpz(i).i[0], power.pp)
+	mi := big2mpz(i)
+	C.element_pp_pow(el.cptr, &mi.i[0], power.pp)
+	mi.free()
 	return el
 }
 
@@ -421,6 +483,7 @@ func (el *Element) PowerBig(power *Power, i *big.Int) *Element {
 func (el *Element) PowerZn(power *Power, i *Element) *Element {
 	if el.checked {
 		el.checkCompatible(power.source)
+		power.ensureOpen()
 		i.checkInteger()
 	}
 	C.element_pp_pow_zn(el.cptr, i.cptr, power.pp)
@@ -436,6 +499,7 @@ func (el *Element) PowerZn(power *Power, i *Element) *Element {
 // y must belong to the pairing's G2 group (or G1 for symmetric pairings).
 func (el *Element) Pair(x, y *Element) *Element {
 	if el.checked {
+		el.ensureOpen()
 		x.ensureChecked()
 		y.ensureChecked()
 		pairing := el.pairing.cptr
@@ -475,6 +539,7 @@ func (el *Element) ProdPair(elements ...*Element) *Element {
 		panic(ErrBadPairList)
 	}
 	if el.checked {
+		el.ensureOpen()
 		pairing := el.pairing.cptr
 		checkFieldsMatch(el.fieldPtr, &pairing.GT[0])
 		for i := 1; i < n; i += 2 {
@@ -515,6 +580,7 @@ func (el *Element) ProdPairSlice(x, y []*Element) *Element {
 		panic(ErrBadPairList)
 	}
 	if el.checked {
+		el.ensureOpen()
 		pairing := el.pairing.cptr
 		checkFieldsMatch(el.fieldPtr, &pairing.GT[0])
 		for i := 1; i < n; i++ {
@@ -549,6 +615,12 @@ type Pairer struct {
 // Source returns the Element for which the pre-processed data was generated.
 func (pairer *Pairer) Source() *Element { return pairer.source }
 
+func (pairer *Pairer) ensureOpen() {
+	if pairer.pp == nil {
+		panic(ErrClosed)
+	}
+}
+
 // Pair sets target = e(s,y) and returns target, where e denotes the pairing
 // operation, and s was the source Element for the Pairer. It is equivalent to
 // target.PairerPair(pairer, y).
@@ -562,8 +634,22 @@ func (pairer *Pairer) Pair(target *Element, y *Element) *Element {
 	return target.PairerPair(pairer, y)
 }
 
+// Close frees the native memory used by the pre-processed data. Close is
+// idempotent. Using a closed Pairer with a checked target panics with
+// ErrClosed.
+func (pairer *Pairer) Close() {
+	if pairer.pp == nil {
+		return
+	}
+	runtime.SetFinalizer(pairer, nil)
+	clearPairer(pairer)
+	pairer.pp = nil
+}
+
 func clearPairer(pairer *Pairer) {
+	recordFree(PairerObject, noField, int64(C.sizeof_struct_pairing_pp_s))
 	C.freePairingPPStruct(pairer.pp)
+	pairer.source.pairing.release()
 }
 
 // PreparePairer generates pre-processing data for repeatedly pairing el. The
@@ -574,13 +660,16 @@ func clearPairer(pairer *Pairer) {
 // el must belong to the pairing's G1 group (or G2 for symmetric pairings).
 func (el *Element) PreparePairer() *Pairer {
 	if el.checked {
+		el.ensureOpen()
 		checkFieldsMatch(el.fieldPtr, el.pairing.cptr.G1)
 	}
+	el.pairing.retain()
 	pairer := &Pairer{
 		source: el,
 		pp:     C.newPairingPPStruct(),
 	}
 	C.pairing_pp_init(pairer.pp, el.cptr, el.pairing.cptr)
+	recordAlloc(PairerObject, noField, int64(C.sizeof_struct_pairing_pp_s))
 	runtime.SetFinalizer(pairer, clearPairer)
 	return pairer
 }
@@ -596,6 +685,8 @@ func (el *Element) PreparePairer() *Pairer {
 // y must belong to the pairing's G2 group (or G1 for symmetric pairings).
 func (el *Element) PairerPair(pairer *Pairer, y *Element) *Element {
 	if el.checked {
+		el.ensureOpen()
+		pairer.ensureOpen()
 		pairer.source.ensureChecked()
 		y.ensureChecked()
 		pairing := el.pairing.cptr
@@ -0,0 +1,290 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+/*
+#include <pbc/pbc.h>
+
+void clearSecretMpz(mpz_t x);
+
+// batchInvert sets dst[i] = 1/src[i] for each i using Montgomery's trick: the
+// running products of the inputs are computed, their product is inverted
+// once, and the individual inverses are recovered by multiplying back. This
+// requires one inversion and 3(n-1) multiplications. The results are computed
+// in temporary storage, so dst and src may overlap arbitrarily.
+void batchInvert(struct element_s** dst, struct element_s** src, int n) {
+	element_t* prefix = malloc(n * sizeof(element_t));
+	element_t inv, tmp;
+	int i;
+	for (i = 0; i < n; i++) element_init_same_as(prefix[i], src[0]);
+	element_init_same_as(inv, src[0]);
+	element_init_same_as(tmp, src[0]);
+
+	element_set(prefix[0], src[0]);
+	for (i = 1; i < n; i++) element_mul(prefix[i], prefix[i-1], src[i]);
+	element_invert(inv, prefix[n-1]);
+	for (i = n - 1; i > 0; i--) {
+		// inv = 1/(src[0]*...*src[i])
+		element_mul(tmp, inv, prefix[i-1]);
+		element_mul(inv, inv, src[i]);
+		element_set(prefix[i], tmp);
+	}
+	element_set(prefix[0], inv);
+
+	for (i = 0; i < n; i++) {
+		element_set(dst[i], prefix[i]);
+		element_clear(prefix[i]);
+	}
+	element_clear(inv);
+	element_clear(tmp);
+	free(prefix);
+}
+
+// pippenger sets out = prod bases[i]^scalars[i] using the bucket method of
+// Pippenger. The scalars are processed in windows of c bits, starting with
+// the most significant. In each window, every base is multiplied into the
+// bucket selected by its digit, and the buckets are combined with a running
+// product so that bucket j contributes its j-th power. This requires roughly
+// (b/c)(n + 2^(c+1)) group operations for n scalars of b bits, compared to
+// about 1.5bn for separate exponentiations.
+static void pippenger(element_ptr out, struct element_s** bases, mpz_t* scalars, int n) {
+	element_t result, running, sum;
+	element_t* buckets;
+	size_t bits = 0, windows, w;
+	int c = 1, i, j, k, numBuckets;
+
+	element_init_same_as(result, out);
+	element_set1(result);
+	for (i = 0; i < n; i++) {
+		if (mpz_sgn(scalars[i]) != 0 && mpz_sizeinbase(scalars[i], 2) > bits) {
+			bits = mpz_sizeinbase(scalars[i], 2);
+		}
+	}
+	while ((4 << c) <= n && c < 16) c++;
+	numBuckets = (1 << c) - 1;
+	windows = (bits + c - 1) / c;
+
+	buckets = malloc(numBuckets * sizeof(element_t));
+	for (j = 0; j < numBuckets; j++) element_init_same_as(buckets[j], out);
+	element_init_same_as(running, out);
+	element_init_same_as(sum, out);
+
+	for (w = windows; w-- > 0; ) {
+		for (k = 0; k < c; k++) element_square(result, result);
+		for (j = 0; j < numBuckets; j++) element_set1(buckets[j]);
+		for (i = 0; i < n; i++) {
+			int digit = 0;
+			for (k = 0; k < c; k++) {
+				if (mpz_tstbit(scalars[i], w * c + k)) digit |= 1 << k;
+			}
+			if (digit != 0) element_mul(buckets[digit-1], buckets[digit-1], bases[i]);
+		}
+		element_set1(running);
+		element_set1(sum);
+		for (j = numBuckets - 1; j >= 0; j--) {
+			element_mul(running, running, buckets[j]);
+			element_mul(sum, sum, running);
+		}
+		element_mul(result, result, sum);
+	}
+
+	element_set(out, result);
+	for (j = 0; j < numBuckets; j++) element_clear(buckets[j]);
+	free(buckets);
+	element_clear(running);
+	element_clear(sum);
+	element_clear(result);
+}
+
+static void clearScalars(mpz_t* scalars, int n) {
+	int i;
+	// The scalars may be secret
+	for (i = 0; i < n; i++) clearSecretMpz(scalars[i]);
+	free(scalars);
+}
+
+// multiExpZn computes the multi-exponentiation for scalars stored as integer
+// elements. If reduce is set, the scalars are reduced modulo the order of the
+// group of out.
+void multiExpZn(element_ptr out, struct element_s** bases, struct element_s** exps, int n, int reduce) {
+	mpz_t* scalars = malloc(n * sizeof(mpz_t));
+	int i;
+	for (i = 0; i < n; i++) {
+		mpz_init2(scalars[i], mpz_sizeinbase(exps[i]->field->order, 2));
+		element_to_mpz(scalars[i], exps[i]);
+		if (reduce) mpz_mod(scalars[i], scalars[i], out->field->order);
+	}
+	pippenger(out, bases, scalars, n);
+	clearScalars(scalars, n);
+}
+
+// multiExpWords computes the multi-exponentiation for non-negative scalars
+// stored as little-endian words. Scalar i consists of lengths[i] words
+// starting at index offsets[i].
+void multiExpWords(element_ptr out, struct element_s** bases, void* words, int* offsets, int* lengths, int n, size_t wordSize) {
+	mpz_t* scalars = malloc(n * sizeof(mpz_t));
+	int i;
+	for (i = 0; i < n; i++) {
+		mpz_init2(scalars[i], lengths[i] * wordSize * 8);
+		mpz_import(scalars[i], lengths[i], -1, wordSize, 0, 0, (char*)words + offsets[i] * wordSize);
+	}
+	pippenger(out, bases, scalars, n);
+	clearScalars(scalars, n);
+}
+*/
+import "C"
+
+import (
+	"math/big"
+	"unsafe"
+)
+
+// elementPointers returns the native pointers of the elements, in a form that
+// can be passed to C in a single call.
+func elementPointers(elements []*Element) []*C.struct_element_s {
+	ptrs := make([]*C.struct_element_s, len(elements))
+	for i, el := range elements {
+		ptrs[i] = el.cptr
+	}
+	return ptrs
+}
+
+// BatchInvert sets dst[i] = 1/src[i] for each i. It uses Montgomery's trick
+// to replace the n inversions with a single inversion and 3(n-1)
+// multiplications, which is much faster than calling Invert on each element
+// when inversions are expensive, as in Zr and GT. The slices may be the same,
+// or overlap in any other way.
+//
+// If any element of src is zero, the results are undefined. For checked
+// elements, BatchInvert panics with ErrDivideByZero instead.
+//
+// Requirements:
+// dst and src must have the same length; and
+// all elements must be from the same algebraic structure.
+func BatchInvert(dst, src []*Element) {
+	n := len(src)
+	if len(dst) != n {
+		panic(ErrLengthMismatch)
+	}
+	if n == 0 {
+		return
+	}
+	if src[0].checked {
+		src[0].ensureChecked()
+		src[0].checkAllCompatible(src...)
+		src[0].checkAllCompatible(dst...)
+		if src[0].field != G1 && src[0].field != G2 {
+			for _, el := range src {
+				if el.Is0() {
+					panic(ErrDivideByZero)
+				}
+			}
+		}
+	}
+	C.batchInvert(&elementPointers(dst)[0], &elementPointers(src)[0], C.int(n))
+}
+
+// MultiExpZn sets el = bases[0]^scalars[0] * bases[1]^scalars[1] * ... and
+// returns el. For curve points, * denotes the group operation. This
+// generalizes Pow2Zn and Pow3Zn to any number of bases using Pippenger's
+// bucket method, which is much faster than separate exponentiations for
+// large inputs; the whole computation is performed in a single call to C. If
+// el belongs to G1, G2, or GT, the scalars are first reduced modulo the order
+// of the group.
+//
+// Requirements:
+// bases and scalars must have the same length;
+// el and the bases must be from the same algebraic structure; and
+// the scalars must be elements of integer mod rings (e.g., Zn for some n,
+// typically the order of the algebraic structure that the bases lie in).
+func (el *Element) MultiExpZn(bases, scalars []*Element) *Element {
+	n := len(bases)
+	if len(scalars) != n {
+		panic(ErrLengthMismatch)
+	}
+	if el.checked {
+		el.checkAllCompatible(bases...)
+		for _, i := range scalars {
+			i.checkInteger()
+		}
+	}
+	if n == 0 {
+		return el.Set1()
+	}
+	reduce := C.int(0)
+	if el.inGroup() {
+		reduce = 1
+	}
+	C.multiExpZn(el.cptr, &elementPointers(bases)[0], &elementPointers(scalars)[0], C.int(n), reduce)
+	return el
+}
+
+// MultiExpBig sets el = bases[0]^scalars[0] * bases[1]^scalars[1] * ... and
+// returns el. See MultiExpZn for details. If el belongs to G1, G2, or GT, the
+// scalars are reduced modulo the order of the group, so negative scalars are
+// allowed; otherwise, MultiExpBig panics with ErrOutOfRange if a scalar is
+// negative.
+//
+// Requirements:
+// bases and scalars must have the same length; and
+// el and the bases must be from the same algebraic structure.
+func (el *Element) MultiExpBig(bases []*Element, scalars []*big.Int) *Element {
+	n := len(bases)
+	if len(scalars) != n {
+		panic(ErrLengthMismatch)
+	}
+	if el.checked {
+		el.checkAllCompatible(bases...)
+	}
+	if n == 0 {
+		return el.Set1()
+	}
+	var order *big.Int
+	if el.inGroup() {
+		order = mpz2big(&mpz{i: &el.cptr.field.order})
+	}
+	words := make([]big.Word, 0, n)
+	offsets := make([]C.int, n)
+	lengths := make([]C.int, n)
+	for i, s := range scalars {
+		if order != nil {
+			s = new(big.Int).Mod(s, order)
+		} else if s.Sign() < 0 {
+			panic(ErrOutOfRange)
+		}
+		b := s.Bits()
+		offsets[i] = C.int(len(words))
+		lengths[i] = C.int(len(b))
+		words = append(words, b...)
+	}
+	// Ensure that there is a valid address to pass even if all scalars are 0
+	words = append(words, 0)
+	C.multiExpWords(el.cptr, &elementPointers(bases)[0], unsafe.Pointer(&words[0]),
+		&offsets[0], &lengths[0], C.int(n), wordSize)
+	return el
+}
+
+// inGroup returns true if el belongs to one of the groups G1, G2, or GT, whose
+// order is the order of the field recorded by PBC.
+func (el *Element) inGroup() bool {
+	return el.field == G1 || el.field == G2 || el.field == GT
+}
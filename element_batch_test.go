@@ -0,0 +1,100 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBatchInvert(t *testing.T) {
+	pairing := testPairing(t)
+	for field, newElement := range map[Field]func() *Element{
+		Zr: pairing.NewZr,
+		GT: pairing.NewGT,
+		G1: pairing.NewG1,
+	} {
+		src := make([]*Element, 7)
+		dst := make([]*Element, len(src))
+		for i := range src {
+			src[i] = newElement().Rand()
+			for src[i].Is0() {
+				src[i].Rand()
+			}
+			dst[i] = newElement()
+		}
+		BatchInvert(dst, src)
+		for i := range src {
+			if !dst[i].Equals(src[i].NewFieldElement().Invert(src[i])) {
+				t.Errorf("field %d: wrong inverse at index %d", field, i)
+			}
+		}
+
+		// In-place inversion
+		BatchInvert(dst, dst)
+		for i := range src {
+			if !dst[i].Equals(src[i]) {
+				t.Errorf("field %d: in-place inversion failed at index %d", field, i)
+			}
+		}
+	}
+
+	a, b := pairing.NewZr().Rand(), pairing.NewZr()
+	expectPanic(t, ErrDivideByZero, func() { BatchInvert([]*Element{a, b}, []*Element{a, b}) })
+	expectPanic(t, ErrLengthMismatch, func() { BatchInvert([]*Element{a}, []*Element{a, b}) })
+	expectPanic(t, ErrIncompatible, func() { BatchInvert([]*Element{a}, []*Element{pairing.NewGT().Rand()}) })
+}
+
+func TestMultiExp(t *testing.T) {
+	pairing := testPairing(t)
+	r := pairing.Order()
+	for field, newElement := range map[Field]func() *Element{
+		G1: pairing.NewG1,
+		GT: pairing.NewGT,
+		Zr: pairing.NewZr,
+	} {
+		for _, n := range []int{0, 1, 3, 40} {
+			bases := make([]*Element, n)
+			scalars := make([]*Element, n)
+			bigScalars := make([]*big.Int, n)
+			expected := newElement().Set1()
+			for i := range bases {
+				bases[i] = newElement().Rand()
+				scalars[i] = pairing.NewZr().Rand()
+				bigScalars[i] = scalars[i].BigInt()
+				if field != Zr {
+					// Out-of-range scalars are reduced
+					bigScalars[i].Sub(bigScalars[i], new(big.Int).Lsh(r, uint(i)))
+				}
+				expected.ThenMul(newElement().PowZn(bases[i], scalars[i]))
+			}
+			if !newElement().MultiExpZn(bases, scalars).Equals(expected) {
+				t.Errorf("field %d, n = %d: MultiExpZn computed the wrong result", field, n)
+			}
+			if !newElement().MultiExpBig(bases, bigScalars).Equals(expected) {
+				t.Errorf("field %d, n = %d: MultiExpBig computed the wrong result", field, n)
+			}
+		}
+	}
+
+	expectPanic(t, ErrLengthMismatch, func() { pairing.NewG1().MultiExpZn([]*Element{pairing.NewG1()}, nil) })
+}
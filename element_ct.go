@@ -0,0 +1,163 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+/*
+#include <stdint.h>
+#include <pbc/pbc.h>
+
+void clearSecretMpz(mpz_t x);
+
+// cswap swaps a and b if bit is 1, without branching on bit. Only the field
+// and data pointers of the elements are exchanged, so the cost does not
+// depend on the size of the elements. The addresses that the following group
+// operation reads and writes do depend on bit, however.
+static void cswap(element_ptr a, element_ptr b, int bit) {
+	uintptr_t mask = -(uintptr_t)bit;
+	uintptr_t* pa = (uintptr_t*)a;
+	uintptr_t* pb = (uintptr_t*)b;
+	size_t i;
+	for (i = 0; i < sizeof(struct element_s) / sizeof(uintptr_t); i++) {
+		uintptr_t t = mask & (pa[i] ^ pb[i]);
+		pa[i] ^= t;
+		pb[i] ^= t;
+	}
+}
+
+// powCT sets out = x^k using a Montgomery ladder. x must belong to a group of
+// order r = out->field->order. The exponent is replaced by e = (k mod r) + r
+// or (k mod r) + 2r, whichever has exactly one more bit than r, so that the
+// ladder always performs the same sequence of operations, and never handles
+// the identity except when x is the identity. The integers derived from k are
+// allocated at their final size and wiped when they are cleared.
+void powCT(element_ptr out, element_ptr x, mpz_t k) {
+	mpz_ptr r = out->field->order;
+	size_t bits = mpz_sizeinbase(r, 2);
+	size_t limbs = bits / GMP_NUMB_BITS + 1;
+	mpz_t e1, e2, e;
+	element_t r0, r1;
+	mp_limb_t* dst;
+	mp_limb_t mask;
+	size_t i;
+
+	mpz_init2(e1, limbs * GMP_NUMB_BITS);
+	mpz_init2(e2, limbs * GMP_NUMB_BITS);
+	mpz_init2(e, limbs * GMP_NUMB_BITS);
+	mpz_mod(e1, k, r);
+	mpz_add(e1, e1, r);
+	mpz_add(e2, e1, r);
+
+	// Select e = e2 if bit `bits` of e1 is clear, using masked limb copies
+	mask = (mp_limb_t)0 - (mp_limb_t)(1 - mpz_tstbit(e1, bits));
+	dst = mpz_limbs_write(e, limbs);
+	for (i = 0; i < limbs; i++) {
+		dst[i] = (mpz_getlimbn(e1, i) & ~mask) | (mpz_getlimbn(e2, i) & mask);
+	}
+	mpz_limbs_finish(e, limbs);
+
+	element_init_same_as(r0, x);
+	element_init_same_as(r1, x);
+	element_set(r0, x);
+	element_square(r1, x);
+	for (i = bits; i-- > 0; ) {
+		int bit = mpz_tstbit(e, i);
+		cswap(r0, r1, bit);
+		element_mul(r1, r0, r1);
+		element_square(r0, r0);
+		cswap(r0, r1, bit);
+	}
+	element_set(out, r0);
+
+	element_clear(r0);
+	element_clear(r1);
+	clearSecretMpz(e1);
+	clearSecretMpz(e2);
+	clearSecretMpz(e);
+}
+
+void powZnCT(element_ptr out, element_ptr x, element_ptr k) {
+	mpz_t z;
+	mpz_init2(z, mpz_sizeinbase(k->field->order, 2));
+	element_to_mpz(z, k);
+	powCT(out, x, z);
+	clearSecretMpz(z);
+}
+*/
+import "C"
+
+import "math/big"
+
+// PowZnCT sets el = x^i and returns el, like PowZn, but uses a Montgomery
+// ladder whose sequence of group operations does not depend on the value of
+// i. It should be used when i is secret, such as when computing signatures or
+// decrypting. PowZnCT is slower than PowZn.
+//
+// The ladder removes the timing dependence introduced by the windowed
+// exponentiation in PBC. However, it exchanges its two intermediate results by
+// swapping pointers, so the memory addresses accessed in each step depend on
+// the bits of i, which may be observable through the cache. PBC also
+// implements the group operations with GMP, which is not constant-time, so
+// small variations depending on the intermediate values remain. Applications
+// that are exposed to precise timing or cache measurements should use a
+// dedicated constant-time implementation.
+//
+// Requirements:
+// el and x must belong to the same group (G1, G2, or GT), and x must lie in
+// the subgroup of order r, as elements obtained from Rand, from hashing, or
+// from the Decode methods do; and
+// i must be an element of an integer mod ring (e.g., Zn for some n).
+func (el *Element) PowZnCT(x, i *Element) *Element {
+	if el.checked {
+		el.checkCompatible(x)
+		i.checkInteger()
+		el.checkGroup()
+	}
+	C.powZnCT(el.cptr, x.cptr, i.cptr)
+	return el
+}
+
+// PowBigCT sets el = x^i and returns el, like PowBig, but without a timing
+// dependence on i. See PowZnCT for details. Negative values of i are allowed.
+//
+// Requirements:
+// el and x must belong to the same group (G1, G2, or GT), and x must lie in
+// the subgroup of order r.
+func (el *Element) PowBigCT(x *Element, i *big.Int) *Element {
+	if el.checked {
+		el.checkCompatible(x)
+		el.checkGroup()
+	}
+	mi := big2mpz(i)
+	if i.Sign() < 0 {
+		C.mpz_neg(&mi.i[0], &mi.i[0])
+	}
+	C.powCT(el.cptr, x.cptr, &mi.i[0])
+	mi.free()
+	return el
+}
+
+// checkGroup panics with ErrIllegalOp unless el belongs to G1, G2, or GT.
+func (el *Element) checkGroup() {
+	if !el.inGroup() {
+		panic(ErrIllegalOp)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestPowCT(t *testing.T) {
+	pairing := testPairing(t)
+	r := pairing.Order()
+	for field, newElement := range map[Field]func() *Element{
+		G1: pairing.NewG1,
+		G2: pairing.NewG2,
+		GT: pairing.NewGT,
+	} {
+		x := newElement().Rand()
+		for _, k := range []int64{0, 1, 2, 640, 641, 642, 1000, -3} {
+			i := big.NewInt(k)
+			expected := newElement().PowBig(x, new(big.Int).Mod(i, r))
+			if !newElement().PowBigCT(x, i).Equals(expected) {
+				t.Errorf("field %d: PowBigCT wrong for exponent %d", field, k)
+			}
+			if k >= 0 && !newElement().PowZnCT(x, pairing.NewZr().SetBig(i)).Equals(expected) {
+				t.Errorf("field %d: PowZnCT wrong for exponent %d", field, k)
+			}
+		}
+		if !newElement().PowBigCT(newElement().Set1(), big.NewInt(5)).Is1() {
+			t.Errorf("field %d: PowBigCT of the identity is not the identity", field)
+		}
+	}
+
+	i := pairing.NewZr().Rand()
+	expectPanic(t, ErrIllegalOp, func() { pairing.NewZr().PowZnCT(i, i) })
+}
+
+// welchT returns Welch's t statistic for two samples.
+func welchT(a, b []float64) float64 {
+	stats := func(x []float64) (mean, variance float64) {
+		for _, v := range x {
+			mean += v
+		}
+		mean /= float64(len(x))
+		for _, v := range x {
+			variance += (v - mean) * (v - mean)
+		}
+		return mean, variance / float64(len(x)-1)
+	}
+	meanA, varA := stats(a)
+	meanB, varB := stats(b)
+	return (meanA - meanB) / math.Sqrt(varA/float64(len(a))+varB/float64(len(b)))
+}
+
+// TestPowCTTiming is a statistical test in the style of dudect: it measures
+// the time taken to exponentiate by the fixed exponent 1 and by random
+// exponents, interleaved in random order, and computes Welch's t statistic
+// for the two distributions. PowZnCT must show no difference in G1, G2, or GT.
+// As a positive control, the same test must detect the difference for the
+// variable-time PowZn.
+func TestPowCTTiming(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping timing test in short mode")
+	}
+	// The toy pairing has too few exponent bits for timing differences to
+	// show, so use realistic parameters with distinct G1 and G2
+	params, err := NamedParams("d159")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairing := params.NewPairing()
+	fixed := pairing.NewZr().Set1()
+	for _, field := range []Field{G1, G2, GT} {
+		x := makeCheckedElement(pairing, field, pairing.fieldPtr(field)).Rand()
+		if tStat := powTiming(x, fixed, (*Element).PowZnCT); math.Abs(tStat) > 10 {
+			t.Errorf("field %d: PowZnCT timing depends on the exponent: t = %.2f", field, tStat)
+		}
+		if tStat := powTiming(x, fixed, (*Element).PowZn); math.Abs(tStat) <= 10 {
+			t.Errorf("field %d: PowZn timing leak was not detected: t = %.2f", field, tStat)
+		}
+	}
+}
+
+// powTiming returns Welch's t statistic comparing the time taken by pow to
+// raise x to fixed and to random exponents. Measurements above the 90th
+// percentile are discarded to reduce the effect of interrupts and scheduling.
+// dudect reports a definite leak for |t| > 10.
+func powTiming(x, fixed *Element, pow func(el, x, i *Element) *Element) float64 {
+	const samples = 2000
+	src := rand.New(rand.NewSource(1))
+	exponents := NewRandSource(src)
+	out := x.NewFieldElement()
+	random := fixed.NewFieldElement()
+	measurements := make([]float64, samples)
+	classes := make([]bool, samples)
+	for n := range measurements {
+		classes[n] = src.Intn(2) == 0
+		i := fixed
+		if !classes[n] {
+			i = random.RandFromSource(exponents)
+		}
+		start := time.Now()
+		pow(out, x, i)
+		measurements[n] = float64(time.Since(start))
+	}
+
+	sorted := append([]float64(nil), measurements...)
+	sort.Float64s(sorted)
+	cutoff := sorted[samples*9/10]
+	var fixedTimes, randomTimes []float64
+	for n, m := range measurements {
+		if m > cutoff {
+			continue
+		}
+		if classes[n] {
+			fixedTimes = append(fixedTimes, m)
+		} else {
+			randomTimes = append(randomTimes, m)
+		}
+	}
+	return welchT(fixedTimes, randomTimes)
+}
@@ -95,6 +95,9 @@ func (el *Element) customFormat(f fmt.State, c rune) {
 // but integers are converted to big.Int for formatting. All of the verbs and
 // flags that can be used in math/big will be used to format the elements.
 func (el *Element) Format(f fmt.State, c rune) {
+	if el.checked {
+		el.ensureOpen()
+	}
 	switch c {
 	case 'v':
 		if f.Flag('#') {
@@ -124,6 +127,9 @@ func (el *Element) String() string {
 // successful, and (nil, false) if an error occurs. s is expected to be in the
 // same format produced by String().
 func (el *Element) SetString(s string, base int) (*Element, bool) {
+	if el.checked {
+		el.ensureOpen()
+	}
 	cstr := C.CString(s)
 	defer C.free(unsafe.Pointer(cstr))
 
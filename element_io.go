@@ -27,6 +27,7 @@ package pbc
 import "C"
 
 import (
+	"bytes"
 	"hash"
 	"math/big"
 	"unsafe"
@@ -46,7 +47,9 @@ func (el *Element) BigInt() *big.Int {
 	}
 	m := newMpz()
 	C.element_to_mpz(&m.i[0], el.cptr)
-	return mpz2big(m)
+	i := mpz2big(m)
+	m.free()
+	return i
 }
 
 // Set sets the value of el to be the same as src.
@@ -83,12 +86,17 @@ func (el *Element) SetBig(i *big.Int) *Element {
 	if el.checked {
 		el.checkInteger()
 	}
-	C.element_set_mpz(el.cptr, &big2mpz(i).i[0])
+	mi := big2mpz(i)
+	C.element_set_mpz(el.cptr, &mi.i[0])
+	mi.free()
 	return el
 }
 
 // SetFromHash generates el deterministically from the bytes in hash.
 func (el *Element) SetFromHash(hash []byte) *Element {
+	if el.checked {
+		el.ensureOpen()
+	}
 	C.element_from_hash(el.cptr, unsafe.Pointer(&hash[0]), C.int(len(hash)))
 	return el
 }
@@ -105,6 +113,9 @@ func (el *Element) SetFromStringHash(s string, h hash.Hash) *Element {
 
 // BytesLen returns the number of bytes needed to represent el.
 func (el *Element) BytesLen() int {
+	if el.checked {
+		el.ensureOpen()
+	}
 	return int(C.element_length_in_bytes(el.cptr))
 }
 
@@ -119,11 +130,36 @@ func (el *Element) Bytes() []byte {
 }
 
 // SetBytes imports a sequence exported by Bytes() and sets the value of el.
+// SetBytes panics with ErrBadLength if buf is shorter than BytesLen(). No
+// other validation is performed; use DecodeBytes for untrusted input.
 func (el *Element) SetBytes(buf []byte) *Element {
+	if len(buf) < el.BytesLen() {
+		panic(ErrBadLength)
+	}
 	C.element_from_bytes(el.cptr, (*C.uchar)(unsafe.Pointer(&buf[0])))
 	return el
 }
 
+// DecodeBytes imports a sequence exported by Bytes() and sets the value of el.
+// Unlike SetBytes, DecodeBytes is intended for untrusted input. It returns
+// (el, nil) if buf is valid, and (nil, err) otherwise, in which case el is
+// left unchanged. The following errors are detected:
+//
+// ErrBadLength if buf is not exactly BytesLen() bytes long;
+// ErrNonCanonical if buf is not the encoding that Bytes() would produce (e.g.,
+// if a coordinate is not reduced);
+// ErrNotOnCurve if el is a point and buf does not encode a point on the
+// curve (the identity has no canonical encoding and is also rejected); and
+// ErrNotInSubgroup if el belongs to G1, G2, or GT and the decoded value does
+// not lie in the subgroup of order r.
+func (el *Element) DecodeBytes(buf []byte) (*Element, error) {
+	return el.decode(buf, el.BytesLen(),
+		func(x *Element) C.int {
+			return C.element_from_bytes(x.cptr, (*C.uchar)(unsafe.Pointer(&buf[0])))
+		},
+		(*Element).Bytes)
+}
+
 // XBytesLen returns the number of bytes needed to represent el's X coordinate.
 //
 // Requirements:
@@ -165,10 +201,33 @@ func (el *Element) SetXBytes(buf []byte) *Element {
 	if el.checked {
 		el.checkPoint()
 	}
+	if len(buf) < el.XBytesLen() {
+		panic(ErrBadLength)
+	}
 	C.element_from_bytes_x_only(el.cptr, (*C.uchar)(unsafe.Pointer(&buf[0])))
 	return el
 }
 
+// DecodeXBytes imports a sequence exported by XBytes() and sets el to be a
+// point on the curve with the given X coordinate. The choice of point is the
+// same as for SetXBytes. Like DecodeBytes, it validates buf and returns an
+// error if buf is malformed, if the X coordinate does not correspond to a
+// point on the curve, or if the point does not lie in the subgroup of order r.
+// el is left unchanged if an error is returned.
+//
+// Requirements:
+// el must be a point on an elliptic curve.
+func (el *Element) DecodeXBytes(buf []byte) (*Element, error) {
+	if el.checked {
+		el.checkPoint()
+	}
+	return el.decode(buf, el.XBytesLen(),
+		func(x *Element) C.int {
+			return C.element_from_bytes_x_only(x.cptr, (*C.uchar)(unsafe.Pointer(&buf[0])))
+		},
+		(*Element).XBytes)
+}
+
 // CompressedBytesLen returns the number of bytes needed to represent a
 // compressed form of el.
 //
@@ -206,6 +265,57 @@ func (el *Element) SetCompressedBytes(buf []byte) *Element {
 	if el.checked {
 		el.checkPoint()
 	}
+	if len(buf) < el.CompressedBytesLen() {
+		panic(ErrBadLength)
+	}
 	C.element_from_bytes_compressed(el.cptr, (*C.uchar)(unsafe.Pointer(&buf[0])))
 	return el
 }
+
+// DecodeCompressedBytes imports a sequence exported by CompressedBytes() and
+// sets the value of el. Like DecodeBytes, it validates buf and returns an
+// error if buf is malformed, if it does not encode a point on the curve, or if
+// the point does not lie in the subgroup of order r. el is left unchanged if
+// an error is returned.
+//
+// Requirements:
+// el must be a point on an elliptic curve.
+func (el *Element) DecodeCompressedBytes(buf []byte) (*Element, error) {
+	if el.checked {
+		el.checkPoint()
+	}
+	return el.decode(buf, el.CompressedBytesLen(),
+		func(x *Element) C.int {
+			return C.element_from_bytes_compressed(x.cptr, (*C.uchar)(unsafe.Pointer(&buf[0])))
+		},
+		(*Element).CompressedBytes)
+}
+
+// decode imports buf into a temporary element using from, and validates the
+// result before copying it into el. Canonicity is checked by re-encoding the
+// temporary element using to and comparing against buf.
+func (el *Element) decode(buf []byte, length int, from func(*Element) C.int, to func(*Element) []byte) (*Element, error) {
+	if len(buf) != length || length == 0 {
+		return nil, ErrBadLength
+	}
+	x := el.NewFieldElement()
+	if int(from(x)) != length {
+		return nil, ErrBadLength
+	}
+	isPoint := el.field == G1 || el.field == G2
+	if isPoint && x.Is0() {
+		// PBC silently maps encodings of invalid points to the identity
+		return nil, ErrNotOnCurve
+	}
+	if !bytes.Equal(to(x), buf) {
+		return nil, ErrNonCanonical
+	}
+	if isPoint && !x.IsValid() {
+		return nil, ErrNotOnCurve
+	}
+	if !x.InSubgroup() {
+		return nil, ErrNotInSubgroup
+	}
+	C.element_set(el.cptr, x.cptr)
+	return el, nil
+}
@@ -34,8 +34,13 @@ func (el *Element) Pairing() *Pairing { return el.pairing }
 // NewFieldElement creates a new element in the same field as el. The new
 // element will be unchecked if and only if el is unchecked.
 func (el *Element) NewFieldElement() *Element {
+	if el.checked {
+		el.ensureOpen()
+	}
 	newElement := makeUncheckedElement(el.pairing, false, G1)
 	C.element_init_same_as(newElement.cptr, el.cptr)
+	newElement.field = el.field
+	newElement.recordAlloc()
 	if el.checked {
 		newElement.checked = true
 		newElement.fieldPtr = el.fieldPtr
@@ -48,6 +53,9 @@ func (el *Element) NewFieldElement() *Element {
 // coordinates. For polynomials, it is the number of coefficients. For infinite
 // points, it is zero. For all other values, it is zero.
 func (el *Element) Len() int {
+	if el.checked {
+		el.ensureOpen()
+	}
 	return int(C.element_item_count(el.cptr))
 }
 
@@ -56,12 +64,18 @@ func (el *Element) Len() int {
 // this operation is invalid. i must be greater than or equal to 0 and less
 // than el.Len(). Bounds checking is only performed for checked elements.
 func (el *Element) Item(i int) *Element {
+	if el.checked {
+		el.ensureOpen()
+	}
 	if el.checked && i >= el.Len() {
 		panic(ErrOutOfRange)
 	}
 	newElement := &Element{
 		pairing: el.pairing,
+		parent:  el,
+		arena:   el.arena,
 		cptr:    C.element_item(el.cptr, C.int(i)),
+		field:   noField,
 	}
 	if newElement.cptr == nil {
 		panic(ErrOutOfRange)
@@ -0,0 +1,55 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+/*
+#include <pbc/pbc.h>
+*/
+import "C"
+
+import "io"
+
+// RandFromSource sets el to a uniformly random value drawn from src, and
+// returns el. Unlike Rand, it does not use the global random source set by
+// SetRandomProvider, so different goroutines can sample from different
+// sources without synchronization. Elements are sampled by PBC in the same
+// way as by Rand; for example, a random element of G1 is obtained by choosing
+// a random point on the curve and multiplying it by the cofactor.
+func (el *Element) RandFromSource(src RandomSource) *Element {
+	if src == nil {
+		panic(ErrIllegalNil)
+	}
+	if el.checked {
+		el.ensureOpen()
+	}
+	withRandomSource(src, func() { C.element_random(el.cptr) })
+	return el
+}
+
+// RandFrom sets el to a uniformly random value using r as the entropy source,
+// and returns el. It is equivalent to el.RandFromSource(NewReaderSource(r)),
+// and panics with ErrEntropyFailure if reading from r fails. Passing
+// crypto/rand.Reader gives cryptographically secure values; passing a
+// deterministic reader gives reproducible values for tests.
+func (el *Element) RandFrom(r io.Reader) *Element {
+	return el.RandFromSource(NewReaderSource(r))
+}
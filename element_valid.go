@@ -0,0 +1,77 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+/*
+#include <pbc/pbc.h>
+*/
+import "C"
+
+// IsValid returns true if el holds a valid value for its algebraic structure.
+// For G1 and G2, this means that el is the identity or a point that satisfies
+// the curve equation; the coefficients of the curve are recovered in the same
+// way as for SetFromHashToGroup. For GT, this means that el is nonzero. All
+// other elements, including those of Zr, are always valid, since PBC keeps
+// them reduced.
+//
+// Elements produced by this package are always valid. IsValid is useful for
+// elements imported with SetBytes or constructed with unchecked operations.
+// Note that valid points may still lie outside the subgroup of order r; use
+// InSubgroup to reject them.
+func (el *Element) IsValid() bool {
+	if el.checked {
+		el.ensureOpen()
+	}
+	switch el.field {
+	case G1, G2:
+		if el.Is0() {
+			return true
+		}
+		m := curveMapper{el.pairing.curveMap(el.field), el.pairing}
+		lhs := m.newElement().Square(el.Item(1))
+		return lhs.Equals(m.g(el.Item(0)))
+	case GT:
+		return !el.Is0()
+	}
+	return true
+}
+
+// InSubgroup returns true if el is valid and, for elements of G1, G2, and GT,
+// lies in the subgroup of order r (or n for type A1 pairings). This is checked
+// by raising el to the power r and comparing the result with the identity.
+// For other elements, InSubgroup is equivalent to IsValid.
+//
+// Validating untrusted points is essential: for many curves, such as those of
+// type A pairings, the cofactor h is large, and an attacker who submits points
+// of small order can learn information about secret exponents. The Decode
+// methods perform this check automatically.
+func (el *Element) InSubgroup() bool {
+	if !el.IsValid() {
+		return false
+	}
+	if !el.inGroup() {
+		return true
+	}
+	x := el.NewFieldElement()
+	C.element_pow_mpz(x.cptr, el.cptr, &el.pairing.cptr.r[0])
+	return x.Is1()
+}
@@ -0,0 +1,94 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import "testing"
+
+func TestValidity(t *testing.T) {
+	pairing := testPairing(t)
+	for _, el := range []*Element{
+		pairing.NewG1().Rand(),
+		pairing.NewG1(),
+		pairing.NewG2().Rand(),
+		pairing.NewGT().Rand(),
+		pairing.NewZr().Rand(),
+	} {
+		if !el.IsValid() || !el.InSubgroup() {
+			t.Errorf("valid element %s was rejected", el)
+		}
+	}
+
+	// A point that is not on the curve
+	p := pairing.NewG1().Rand()
+	for p.Is0() {
+		p.Rand()
+	}
+	p.Item(1).ThenAdd(p.Item(1).NewFieldElement().Set1())
+	if p.IsValid() || p.InSubgroup() {
+		t.Error("point off the curve was accepted")
+	}
+
+	// A point on the curve whose order is not r, obtained by mapping to the
+	// curve without clearing the cofactor
+	cm, err := pairing.hashMap(G1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := curveMapper{cm, pairing}
+	q := m.mapToCurve(m.hashToField([]byte("test"), []byte("TEST-DST"), 1)[0])
+	if !q.IsValid() {
+		t.Error("mapped point is not on the curve")
+	}
+	if q.InSubgroup() {
+		t.Error("point outside the subgroup was accepted")
+	}
+
+	// Elements of the extension field that are not in GT
+	if pairing.NewGT().Set0().InSubgroup() {
+		t.Error("zero was accepted in GT")
+	}
+	g := pairing.NewGT().Rand()
+	g.Item(0).Set1()
+	g.Item(1).Set1()
+	if g.InSubgroup() {
+		t.Error("element outside GT was accepted")
+	}
+}
+
+func TestValidityWithoutHashing(t *testing.T) {
+	// Validation only needs the curve coefficients, so it must not depend on
+	// the constants used for hashing, which may be unavailable
+	params, err := NamedParams("d159")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairing := params.NewPairing()
+	for _, field := range []Field{G1, G2} {
+		p := makeCheckedElement(pairing, field, pairing.fieldPtr(field)).Rand()
+		if _, err := p.NewFieldElement().DecodeBytes(p.Bytes()); err != nil {
+			t.Errorf("valid point in field %d was rejected: %v", field, err)
+		}
+		if m := pairing.curveMaps[field].m; m == nil || m.z != nil || m.cofactor != nil {
+			t.Errorf("validating field %d prepared the hash constants", field)
+		}
+	}
+}
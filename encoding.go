@@ -0,0 +1,260 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Elements are marshaled in a self-describing envelope so that the encoded
+// form can be checked against the target before it is decoded. The envelope
+// consists of a version byte, the Field of the element, the encoding of the
+// payload, the Fingerprint of the pairing, and the payload itself. Version 1
+// envelopes, which lack the fingerprint, are still accepted.
+const (
+	envelopeVersion1   byte = 1
+	envelopeVersion2   byte = 2
+	envelopeHeaderSize      = 3
+	envelopeV2Size          = envelopeHeaderSize + len(Fingerprint{})
+)
+
+const (
+	encodingFull       byte = iota
+	encodingCompressed byte = iota
+	encodingIdentity   byte = iota
+)
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. Points in
+// G1 and G2 are stored in compressed form; other elements are stored in the
+// format produced by Bytes(). The identity of G1 and G2, which has no
+// compressed form, is stored with an empty payload.
+func (el *Element) MarshalBinary() ([]byte, error) {
+	if el.cptr == nil {
+		return nil, ErrUninitialized
+	}
+	if el.field == noField {
+		return nil, ErrIllegalOp
+	}
+	encoding := encodingFull
+	var payload []byte
+	if el.field == G1 || el.field == G2 {
+		encoding = encodingCompressed
+		if el.Is0() {
+			encoding = encodingIdentity
+		} else {
+			payload = el.CompressedBytes()
+		}
+	} else {
+		payload = el.Bytes()
+	}
+	data := make([]byte, envelopeHeaderSize, envelopeV2Size+len(payload))
+	data[0] = envelopeVersion2
+	data[1] = byte(el.field)
+	data[2] = encoding
+	data = append(data, el.pairing.fingerprint[:]...)
+	return append(data, payload...), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. The
+// data must have been produced by MarshalBinary. The payload is validated in
+// the same way as DecodeBytes, so UnmarshalBinary is suitable for untrusted
+// input.
+//
+// If el is already initialized (e.g., by NewG1), the data must describe an
+// element in the same field and pairing as el; otherwise, ErrIncompatible is
+// returned. If el is the zero value of Element, the pairing is located using
+// the fingerprint stored in the data and el is initialized as a checked
+// element in the stored field. The pairing must have been registered with
+// RegisterPairing, or ErrUnknownPairing is returned.
+func (el *Element) UnmarshalBinary(data []byte) error {
+	if len(data) < envelopeHeaderSize {
+		return ErrBadEncoding
+	}
+	field := Field(data[1])
+	var fingerprint *Fingerprint
+	var payload []byte
+	switch data[0] {
+	case envelopeVersion1:
+		payload = data[envelopeHeaderSize:]
+	case envelopeVersion2:
+		if len(data) < envelopeV2Size {
+			return ErrBadEncoding
+		}
+		fingerprint = new(Fingerprint)
+		copy(fingerprint[:], data[envelopeHeaderSize:envelopeV2Size])
+		payload = data[envelopeV2Size:]
+	default:
+		return ErrBadEncoding
+	}
+	if field < G1 || field > Zr {
+		return ErrBadEncoding
+	}
+
+	target := el
+	if el.cptr == nil {
+		if fingerprint == nil {
+			return ErrUninitialized
+		}
+		pairing, ok := LookupPairing(*fingerprint)
+		if !ok {
+			return ErrUnknownPairing
+		}
+		target = makeCheckedElement(pairing, field, pairing.fieldPtr(field))
+	} else if field != el.field || (fingerprint != nil && *fingerprint != el.pairing.fingerprint) {
+		return ErrIncompatible
+	}
+
+	var err error
+	switch {
+	case data[2] == encodingFull:
+		_, err = target.DecodeBytes(payload)
+	case data[2] == encodingCompressed && (field == G1 || field == G2):
+		_, err = target.DecodeCompressedBytes(payload)
+	case data[2] == encodingIdentity && (field == G1 || field == G2) && len(payload) == 0:
+		target.Set0()
+	default:
+		err = ErrBadEncoding
+	}
+	if target != el {
+		if err != nil {
+			target.Close()
+			return err
+		}
+		el.adopt(target)
+	}
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. The text form
+// is the base64 encoding of the output of MarshalBinary.
+func (el *Element) MarshalText() ([]byte, error) {
+	data, err := el.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	text := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+	base64.StdEncoding.Encode(text, data)
+	return text, nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. See
+// UnmarshalBinary for the requirements on el.
+func (el *Element) UnmarshalText(text []byte) error {
+	data := make([]byte, base64.StdEncoding.DecodedLen(len(text)))
+	n, err := base64.StdEncoding.Decode(data, text)
+	if err != nil {
+		return ErrBadEncoding
+	}
+	return el.UnmarshalBinary(data[:n])
+}
+
+// MarshalJSON implements the json.Marshaler interface. Elements are encoded
+// as JSON strings containing the output of MarshalText.
+func (el *Element) MarshalJSON() ([]byte, error) {
+	text, err := el.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. See
+// UnmarshalBinary for the requirements on el.
+func (el *Element) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return el.UnmarshalText([]byte(text))
+}
+
+// adopt makes the zero value el share the C structure of src, which must not
+// be used afterwards. el may not be the start of a heap allocation (e.g., when
+// it is a field of a struct), so src keeps the finalizer and is freed when el
+// is closed.
+func (el *Element) adopt(src *Element) {
+	*el = *src
+	el.owner = src
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The
+// parameters are stored in the PBC text format produced by String().
+func (params *Params) MarshalBinary() ([]byte, error) {
+	return params.MarshalText()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. See
+// UnmarshalText for the requirements on params.
+func (params *Params) UnmarshalBinary(data []byte) error {
+	return params.UnmarshalText(data)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. The parameters
+// are stored in the PBC text format produced by String().
+func (params *Params) MarshalText() ([]byte, error) {
+	if params.cptr == nil {
+		return nil, ErrUninitialized
+	}
+	return []byte(params.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. params must
+// be the zero value of Params; otherwise, ErrInitialized is returned, or
+// ErrClosed if params has been closed.
+func (params *Params) UnmarshalText(text []byte) error {
+	if params.closed {
+		return ErrClosed
+	}
+	if params.cptr != nil {
+		return ErrInitialized
+	}
+	parsed, err := NewParamsFromString(string(text))
+	if err != nil {
+		return err
+	}
+	// params may not be the start of a heap allocation, so parsed keeps
+	// ownership of the C structure and its finalizer
+	params.cptr = parsed.cptr
+	params.owner = parsed
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. Parameters are encoded
+// as JSON strings in the PBC text format.
+func (params *Params) MarshalJSON() ([]byte, error) {
+	text, err := params.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. See UnmarshalText
+// for the requirements on params.
+func (params *Params) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return params.UnmarshalText([]byte(text))
+}
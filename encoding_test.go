@@ -0,0 +1,233 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeBytes(t *testing.T) {
+	pairing := testPairing(t)
+
+	g := pairing.NewG1().Rand()
+	if x, err := pairing.NewG1().DecodeBytes(g.Bytes()); err != nil || !x.Equals(g) {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if x, err := pairing.NewG1().DecodeCompressedBytes(g.CompressedBytes()); err != nil || !x.Equals(g) {
+		t.Fatalf("compressed round trip failed: %v", err)
+	}
+
+	buf := g.Bytes()
+	if _, err := pairing.NewG1().DecodeBytes(buf[:len(buf)-1]); err != ErrBadLength {
+		t.Fatalf("expected ErrBadLength, got %v", err)
+	}
+	buf[len(buf)-1] ^= 1
+	if _, err := pairing.NewG1().DecodeBytes(buf); err != ErrNotOnCurve {
+		t.Fatalf("expected ErrNotOnCurve, got %v", err)
+	}
+
+	// (0,0) lies on y^2 = x^3 + x but has order 2
+	if _, err := pairing.NewG1().DecodeBytes(make([]byte, len(buf))); err != ErrNotInSubgroup {
+		t.Fatalf("expected ErrNotInSubgroup, got %v", err)
+	}
+
+	zr := pairing.NewZr()
+	unreduced := make([]byte, zr.BytesLen())
+	for i := range unreduced {
+		unreduced[i] = 0xff
+	}
+	if _, err := zr.DecodeBytes(unreduced); err != ErrNonCanonical {
+		t.Fatalf("expected ErrNonCanonical, got %v", err)
+	}
+}
+
+func TestElementMarshaling(t *testing.T) {
+	pairing := testPairing(t)
+
+	type message struct {
+		Point  *Element
+		Scalar *Element
+		Params *Params
+	}
+	in := message{
+		Point:  pairing.NewG1().Rand(),
+		Scalar: pairing.NewZr().Rand(),
+		Params: pairing.Params(),
+	}
+	data, err := json.Marshal(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := message{Point: pairing.NewG1(), Scalar: pairing.NewZr()}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.Point.Equals(in.Point) || !out.Scalar.Equals(in.Scalar) {
+		t.Fatal("elements did not survive round trip")
+	}
+	if out.Params.String() != in.Params.String() {
+		t.Fatal("parameters did not survive round trip")
+	}
+
+	binary, err := in.Point.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pairing.NewGT().UnmarshalBinary(binary); err != ErrIncompatible {
+		t.Fatalf("expected ErrIncompatible, got %v", err)
+	}
+	if err := new(Element).UnmarshalBinary(binary); err != ErrUnknownPairing {
+		t.Fatalf("expected ErrUnknownPairing, got %v", err)
+	}
+}
+
+func TestParamsUnmarshaling(t *testing.T) {
+	text, err := testPairing(t).Params().MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Params value is not the start of an allocation
+	var holder struct {
+		N      int
+		Params Params
+	}
+	if err := holder.Params.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if holder.Params.String() != string(text) {
+		t.Fatal("parameters did not survive round trip")
+	}
+	if err := holder.Params.UnmarshalText(text); err != ErrInitialized {
+		t.Fatalf("expected ErrInitialized, got %v", err)
+	}
+	holder.Params.Close()
+	holder.Params.Close()
+	if err := holder.Params.UnmarshalText(text); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestIdentityMarshaling(t *testing.T) {
+	asymmetric, err := NamedParams("d159")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pairing := range []*Pairing{testPairing(t), asymmetric.NewPairing()} {
+		for _, id := range []*Element{pairing.NewG1().Set0(), pairing.NewG2().Set0(), pairing.NewGT().Set1()} {
+			binary, err := id.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := id.NewFieldElement().Rand()
+			if err := out.UnmarshalBinary(binary); err != nil || !out.Equals(id) {
+				t.Fatalf("identity in field %d did not survive binary round trip: %v", id.Field(), err)
+			}
+
+			data, err := json.Marshal(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out = id.NewFieldElement().Rand()
+			if err := json.Unmarshal(data, out); err != nil || !out.Equals(id) {
+				t.Fatalf("identity in field %d did not survive JSON round trip: %v", id.Field(), err)
+			}
+
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(id); err != nil {
+				t.Fatal(err)
+			}
+			out = id.NewFieldElement().Rand()
+			if err := gob.NewDecoder(&buf).Decode(out); err != nil || !out.Equals(id) {
+				t.Fatalf("identity in field %d did not survive gob round trip: %v", id.Field(), err)
+			}
+
+			if id.Field() != GT {
+				if err := out.UnmarshalBinary(append(binary, 0)); err != ErrBadEncoding {
+					t.Fatalf("identity with a payload returned %v", err)
+				}
+			}
+		}
+	}
+}
+
+func TestPairingRegistry(t *testing.T) {
+	pairing := testPairing(t)
+	fingerprint := RegisterPairing(pairing)
+	defer UnregisterPairing(fingerprint)
+
+	if found, ok := LookupPairing(fingerprint); !ok || found != pairing {
+		t.Fatal("registered pairing was not found")
+	}
+
+	type message struct {
+		Signature *Element
+		Key       *Element
+	}
+	in := message{Signature: pairing.NewG1().Rand(), Key: pairing.NewGT().Rand()}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&in); err != nil {
+		t.Fatal(err)
+	}
+	var out message
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Signature.Field() != G1 || !out.Signature.Equals(in.Signature) || !out.Key.Equals(in.Key) {
+		t.Fatal("elements did not survive round trip")
+	}
+
+	// The Element value is not the start of an allocation
+	binary, err := in.Signature.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var holder struct {
+		N       int
+		Element Element
+	}
+	if err := holder.Element.UnmarshalBinary(binary); err != nil || !holder.Element.Equals(in.Signature) {
+		t.Fatalf("element decoded in place did not survive round trip: %v", err)
+	}
+	holder.Element.Close()
+	holder.Element.Close()
+
+	// A failed decode leaves the zero value untouched
+	var failed Element
+	if err := failed.UnmarshalBinary(binary[:len(binary)-1]); err == nil {
+		t.Fatal("truncated data was accepted")
+	}
+	if failed.cptr != nil {
+		t.Fatal("failed decode initialized the element")
+	}
+
+	pairing.Close()
+	if _, ok := LookupPairing(fingerprint); ok {
+		t.Fatal("closed pairing was found")
+	}
+	if err := new(Element).UnmarshalBinary(binary); err != ErrUnknownPairing {
+		t.Fatalf("expected ErrUnknownPairing, got %v", err)
+	}
+}
@@ -25,6 +25,8 @@ import "errors"
 
 var (
 	ErrInvalidParamString = errors.New("invalid pairing parameters")
+	ErrWrongParamsType    = errors.New("parameters describe a different type of pairing")
+	ErrInvalidParams      = errors.New("pairing parameters are inconsistent")
 	ErrNoSuitableCurves   = errors.New("no suitable curves were found")
 	ErrUnknownField       = errors.New("unchecked element initialized in unknown field")
 	ErrIllegalOp          = errors.New("operation is illegal for elements of this type")
@@ -35,7 +37,25 @@ var (
 	ErrBadVerb            = errors.New("invalid verb specified for scan")
 	ErrIllegalNil         = errors.New("received nil when non-nil was expected")
 	ErrOutOfRange         = errors.New("index out of range")
+	ErrLengthMismatch     = errors.New("slices have different lengths")
+	ErrDivideByZero       = errors.New("division by zero")
 	ErrEntropyFailure     = errors.New("error while reading from entropy source")
+	ErrShortSeed          = errors.New("seed does not contain enough entropy")
+	ErrReseedRequired     = errors.New("random generator must be reseeded")
+	ErrUnknownAlgorithm   = errors.New("provenance record uses an unknown algorithm")
+	ErrProvenanceMismatch = errors.New("parameters do not match their provenance record")
 	ErrHashFailure        = errors.New("error while hashing data")
+	ErrNoHashToCurve      = errors.New("hashing to this group is not supported")
+	ErrBadLength          = errors.New("encoded element has the wrong length")
+	ErrNonCanonical       = errors.New("encoded element is not in canonical form")
+	ErrNotOnCurve         = errors.New("encoded point does not lie on the curve")
+	ErrNotInSubgroup      = errors.New("element does not lie in the subgroup of order r")
+	ErrBadEncoding        = errors.New("encoded element is malformed")
+	ErrUninitialized      = errors.New("target has not been initialized")
+	ErrInitialized        = errors.New("target has already been initialized")
+	ErrUnknownPairing     = errors.New("no registered pairing matches the fingerprint")
+	ErrUnknownParams      = errors.New("no parameter set has the given name")
+	ErrInsecureParams     = errors.New("parameters do not meet the minimum security level")
+	ErrClosed             = errors.New("object has been closed")
 	ErrInternal           = errors.New("a severe internal error has lead to possible memory corruption")
 )
@@ -30,14 +30,21 @@ typedef struct {
 	pbc_param_ptr params;
 	uint32_t rbits;
 	uint32_t qbits;
+	uintptr_t handle;
 } check_pairing_settings_t;
 
+extern int goCMCandidate(uintptr_t handle, int accepted);
+
 int checkPairing(pbc_cm_t cm, void* p) {
 	check_pairing_settings_t* settings = (check_pairing_settings_t*)p;
 
 	unsigned int rbits = (unsigned int)mpz_sizeinbase(cm->r, 2);
 	unsigned int qbits = (unsigned int)mpz_sizeinbase(cm->q, 2);
-	if (rbits < settings->rbits || qbits < settings->qbits) return 0;
+	int accepted = rbits >= settings->rbits && qbits >= settings->qbits;
+
+	// Report progress and stop the search if it has been cancelled
+	if (settings->handle != 0 && goCMCandidate(settings->handle, accepted)) return 2;
+	if (!accepted) return 0;
 
 	if (settings->typeD) {
 		pbc_param_init_d_gen(settings->params, cm);
@@ -46,11 +53,39 @@ int checkPairing(pbc_cm_t cm, void* p) {
 	}
 	return 1;
 }
+
+extern int goCMCollect(uintptr_t handle, struct pbc_cm_s* cm);
+
+// collectCM passes every candidate found by a CM search to Go, and continues
+// the search.
+int collectCM(pbc_cm_t cm, void* p) {
+	return goCMCollect(*(uintptr_t*)p, cm);
+}
+
+// paramsFromCM generates type D or G parameters from the values describing a
+// curve found by a CM search.
+void paramsFromCM(pbc_param_ptr params, int typeD, mpz_t q, mpz_t n, mpz_t h, mpz_t r, int D, int k) {
+	pbc_cm_t cm;
+	pbc_cm_init(cm);
+	mpz_set(cm->q, q);
+	mpz_set(cm->n, n);
+	mpz_set(cm->h, h);
+	mpz_set(cm->r, r);
+	cm->D = D;
+	cm->k = k;
+	if (typeD) {
+		pbc_param_init_d_gen(params, cm);
+	} else {
+		pbc_param_init_g_gen(params, cm);
+	}
+	pbc_cm_clear(cm);
+}
 */
 import "C"
 
 import (
 	"math/big"
+	"runtime/cgo"
 	"unsafe"
 )
 
@@ -82,7 +117,9 @@ func GenerateA(rbits uint32, qbits uint32) *Params {
 // More details: https://crypto.stanford.edu/pbc/manual/ch08s03.html
 func GenerateA1(r *big.Int) *Params {
 	params := makeParams()
-	C.pbc_param_init_a1_gen(params.cptr, &big2mpz(r).i[0])
+	mr := big2mpz(r)
+	C.pbc_param_init_a1_gen(params.cptr, &mr.i[0])
+	mr.free()
 	return params
 }
 
@@ -109,7 +146,7 @@ func GenerateA1(r *big.Int) *Params {
 //
 // More details: https://crypto.stanford.edu/pbc/manual/ch08s06.html
 func GenerateD(d uint32, rbits uint32, qbits uint32, bitlimit uint32) (*Params, error) {
-	return generateWithCM(true, d, rbits, qbits, bitlimit)
+	return generateWithCM(true, d, rbits, qbits, bitlimit, nil)
 }
 
 // GenerateE generates a pairing entirely within a order r subgroup of an order
@@ -166,21 +203,34 @@ func GenerateF(bits uint32) *Params {
 //
 // More details: https://crypto.stanford.edu/pbc/manual/ch08s09.html
 func GenerateG(d uint32, rbits uint32, qbits uint32, bitlimit uint32) (*Params, error) {
-	return generateWithCM(false, d, qbits, rbits, bitlimit)
+	return generateWithCM(false, d, rbits, qbits, bitlimit, nil)
 }
 
-func generateWithCM(typeD bool, d uint32, rbits uint32, qbits uint32, bitlimit uint32) (*Params, error) {
+// generateWithCM searches for a type D or G curve with the CM method. If
+// search is non-nil, it receives progress reports and can stop the search.
+func generateWithCM(typeD bool, d uint32, rbits uint32, qbits uint32, bitlimit uint32, search *cmSearch) (*Params, error) {
 	params := makeParams()
 	settings := &C.check_pairing_settings_t{
 		params: params.cptr,
 		rbits:  C.uint32_t(rbits),
 		qbits:  C.uint32_t(qbits),
 	}
+	if search != nil {
+		handle := cgo.NewHandle(search)
+		defer handle.Delete()
+		settings.handle = C.uintptr_t(handle)
+	}
+	var res C.int
 	if typeD {
 		settings.typeD = C.int(1)
+		res = C.pbc_cm_search_d((*[0]byte)(C.checkPairing), unsafe.Pointer(settings), C.uint(d), C.uint(bitlimit))
+	} else {
+		res = C.pbc_cm_search_g((*[0]byte)(C.checkPairing), unsafe.Pointer(settings), C.uint(d), C.uint(bitlimit))
 	}
-	res := C.pbc_cm_search_d((*[0]byte)(C.checkPairing), unsafe.Pointer(settings), C.uint(d), C.uint(bitlimit))
 	if res != 1 {
+		if search != nil && search.ctx.Err() != nil {
+			return nil, search.ctx.Err()
+		}
 		return nil, ErrNoSuitableCurves
 	}
 	return params, nil
@@ -0,0 +1,205 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+/*
+#include <stdint.h>
+*/
+import "C"
+
+import (
+	"context"
+	"math/big"
+	"runtime/cgo"
+)
+
+// CMProgress reports the progress of a search for type D or G parameters.
+// The CM method enumerates candidate curves for the discriminant; each
+// candidate is either accepted, ending the search, or rejected because its
+// group order or field size is smaller than requested.
+type CMProgress struct {
+	Discriminant uint32
+	Candidates   int // number of candidate curves examined so far
+	Rejected     int // number of candidates rejected by the size requirements
+}
+
+// cmSearch holds the state of a cancellable CM search. It is passed to the C
+// callback through a cgo.Handle.
+type cmSearch struct {
+	ctx      context.Context
+	progress CMProgress
+	report   func(CMProgress)
+}
+
+//export goCMCandidate
+func goCMCandidate(handle C.uintptr_t, accepted C.int) C.int {
+	search := cgo.Handle(handle).Value().(*cmSearch)
+	if search.ctx.Err() != nil {
+		return 1
+	}
+	search.progress.Candidates++
+	if accepted == 0 {
+		search.progress.Rejected++
+	}
+	if search.report != nil {
+		search.report(search.progress)
+	}
+	return 0
+}
+
+// runGeneration runs generate in a new goroutine, and waits until it finishes
+// or ctx is done. After cancellation, generate keeps running in the background
+// until it notices that ctx is done, and its result is discarded.
+func runGeneration(ctx context.Context, generate func() (*Params, error)) (*Params, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	type result struct {
+		params *Params
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		params, err := generate()
+		done <- result{params, err}
+	}()
+	select {
+	case r := <-done:
+		return r.params, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// GenerationProgress reports the progress of the generation of type A, A1, E,
+// or F parameters. PBC generates these parameters by drawing random candidates
+// until one satisfies the requirements, so the number of random numbers drawn
+// indicates how much work has been done.
+type GenerationProgress struct {
+	Draws int // number of random numbers drawn so far
+}
+
+// generationCancelled is the panic value used to abandon a PBC generator from
+// within the random source once its context is done.
+type generationCancelled struct{}
+
+// cancellableSource draws random numbers from the global source on behalf of a
+// generation that can be cancelled. It checks the context and reports the
+// progress before each draw.
+type cancellableSource struct {
+	ctx      context.Context
+	progress GenerationProgress
+	report   func(GenerationProgress)
+}
+
+func (src *cancellableSource) Rand(limit *big.Int) *big.Int {
+	if src.ctx.Err() != nil {
+		panic(generationCancelled{})
+	}
+	src.progress.Draws++
+	if src.report != nil {
+		src.report(src.progress)
+	}
+	provider := RandomProvider()
+	if provider == nil {
+		provider = defaultRandomSource
+	}
+	return provider.Rand(limit)
+}
+
+// generateCancellable runs the PBC generator described by record, and returns
+// ctx.Err() if ctx is done before it completes. PBC is abandoned at its next
+// random draw after cancellation; the memory that it had allocated is leaked.
+func generateCancellable(ctx context.Context, record *Provenance, report func(GenerationProgress)) (params *Params, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(generationCancelled); !ok {
+				panic(r)
+			}
+			params, err = nil, ctx.Err()
+		}
+	}()
+	params, err = generateWithSource(&cancellableSource{ctx: ctx, report: report}, record)
+	if err == nil && ctx.Err() != nil {
+		params.Close()
+		params, err = nil, ctx.Err()
+	}
+	return
+}
+
+// GenerateAContext is like GenerateA, but can be cancelled, and reports its
+// progress. If ctx is done before the parameters have been generated,
+// GenerateAContext returns ctx.Err() immediately, and PBC stops the next time
+// that it draws a random number. If progress is non-nil, it is called before
+// each random number is drawn. It is called from a different goroutine than
+// the caller's, but never concurrently with itself.
+//
+// Random numbers are drawn from the global source (see SetRandomProvider).
+func GenerateAContext(ctx context.Context, rbits uint32, qbits uint32, progress func(GenerationProgress)) (*Params, error) {
+	record := &Provenance{Type: TypeA, RBits: rbits, QBits: qbits}
+	return runGeneration(ctx, func() (*Params, error) { return generateCancellable(ctx, record, progress) })
+}
+
+// GenerateA1Context is like GenerateA1, but can be cancelled, and reports its
+// progress. See GenerateAContext for details.
+func GenerateA1Context(ctx context.Context, r *big.Int, progress func(GenerationProgress)) (*Params, error) {
+	record := &Provenance{Type: TypeA1, R: r}
+	return runGeneration(ctx, func() (*Params, error) { return generateCancellable(ctx, record, progress) })
+}
+
+// GenerateEContext is like GenerateE, but can be cancelled, and reports its
+// progress. See GenerateAContext for details.
+func GenerateEContext(ctx context.Context, rbits uint32, qbits uint32, progress func(GenerationProgress)) (*Params, error) {
+	record := &Provenance{Type: TypeE, RBits: rbits, QBits: qbits}
+	return runGeneration(ctx, func() (*Params, error) { return generateCancellable(ctx, record, progress) })
+}
+
+// GenerateFContext is like GenerateF, but can be cancelled, and reports its
+// progress. See GenerateAContext for details. PBC draws few random numbers
+// while it searches for type F parameters, so it may not stop until the
+// search is nearly complete.
+func GenerateFContext(ctx context.Context, bits uint32, progress func(GenerationProgress)) (*Params, error) {
+	record := &Provenance{Type: TypeF, Bits: bits}
+	return runGeneration(ctx, func() (*Params, error) { return generateCancellable(ctx, record, progress) })
+}
+
+// GenerateDContext is like GenerateD, but can be cancelled, and reports its
+// progress. If ctx is done before a curve is found, GenerateDContext returns
+// ctx.Err() immediately, and the search stops when it examines its next
+// candidate. If progress is non-nil, it is called after each candidate is
+// examined. It is called from a different goroutine than the caller's, but
+// never concurrently with itself.
+func GenerateDContext(ctx context.Context, d uint32, rbits uint32, qbits uint32, bitlimit uint32, progress func(CMProgress)) (*Params, error) {
+	search := &cmSearch{ctx: ctx, report: progress, progress: CMProgress{Discriminant: d}}
+	return runGeneration(ctx, func() (*Params, error) {
+		return generateWithCM(true, d, rbits, qbits, bitlimit, search)
+	})
+}
+
+// GenerateGContext is like GenerateG, but can be cancelled, and reports its
+// progress. See GenerateDContext for details.
+func GenerateGContext(ctx context.Context, d uint32, rbits uint32, qbits uint32, bitlimit uint32, progress func(CMProgress)) (*Params, error) {
+	search := &cmSearch{ctx: ctx, report: progress, progress: CMProgress{Discriminant: d}}
+	return runGeneration(ctx, func() (*Params, error) {
+		return generateWithCM(false, d, rbits, qbits, bitlimit, search)
+	})
+}
@@ -0,0 +1,195 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+/*
+#include <pbc/pbc.h>
+
+struct pbc_param_s* newParamStruct();
+*/
+import "C"
+
+import (
+	"fmt"
+	"math/big"
+	"runtime"
+)
+
+// ProvenanceAlgorithm identifies the procedure used by the seeded generation
+// functions. It changes whenever the procedure changes in a way that affects
+// the generated parameters.
+const ProvenanceAlgorithm = "pbc-go/seeded-generation/hmac-drbg-sha256/v1"
+
+// Provenance records how a set of parameters was generated from a seed, so
+// that anyone can repeat the generation with VerifyGeneration. Publishing the
+// record alongside the parameters gives "nothing up my sleeve" assurance that
+// the parameters were not chosen to have hidden properties, provided that the
+// seed itself was chosen in a verifiable way (for example, as the hash of a
+// public statement).
+//
+// The parameters are generated by PBC using an HMAC_DRBG (see DRBG) that is
+// instantiated with the seed, and a personalization string derived from the
+// algorithm and the generation arguments. Reproducing the parameters requires
+// the same version of PBC.
+type Provenance struct {
+	Algorithm string
+	Type      PairingType
+	Seed      []byte
+
+	// The arguments to the generation function. Only those used by Type are
+	// set.
+	RBits uint32   `json:",omitempty"`
+	QBits uint32   `json:",omitempty"`
+	Bits  uint32   `json:",omitempty"`
+	R     *big.Int `json:",omitempty"`
+
+	// Draws is the number of random numbers drawn during generation. It
+	// reflects the number of candidates that PBC tried before succeeding.
+	Draws int
+
+	// Fingerprint is the fingerprint of the resulting parameters.
+	Fingerprint Fingerprint
+}
+
+// countingSource counts the random numbers drawn from a source.
+type countingSource struct {
+	source RandomSource
+	draws  int
+}
+
+func (src *countingSource) Rand(limit *big.Int) *big.Int {
+	src.draws++
+	return src.source.Rand(limit)
+}
+
+// GenerateASeeded is like GenerateA, but draws all randomness from a DRBG
+// instantiated with seed, which must be at least 16 bytes long. It returns the
+// parameters together with a record of their provenance.
+//
+// The global random source is replaced for the duration of the generation, so
+// other calls that draw random numbers block until it completes. For the
+// result to be reproducible, no other operations that use randomness
+// internally, such as computing square roots, may run concurrently.
+func GenerateASeeded(seed []byte, rbits uint32, qbits uint32) (*Params, *Provenance, error) {
+	return generateSeeded(&Provenance{Type: TypeA, Seed: seed, RBits: rbits, QBits: qbits})
+}
+
+// GenerateA1Seeded is like GenerateA1, but draws all randomness from a DRBG
+// instantiated with seed. See GenerateASeeded for details.
+func GenerateA1Seeded(seed []byte, r *big.Int) (*Params, *Provenance, error) {
+	return generateSeeded(&Provenance{Type: TypeA1, Seed: seed, R: new(big.Int).Set(r)})
+}
+
+// GenerateESeeded is like GenerateE, but draws all randomness from a DRBG
+// instantiated with seed. See GenerateASeeded for details.
+func GenerateESeeded(seed []byte, rbits uint32, qbits uint32) (*Params, *Provenance, error) {
+	return generateSeeded(&Provenance{Type: TypeE, Seed: seed, RBits: rbits, QBits: qbits})
+}
+
+// GenerateFSeeded is like GenerateF, but draws all randomness from a DRBG
+// instantiated with seed. See GenerateASeeded for details.
+func GenerateFSeeded(seed []byte, bits uint32) (*Params, *Provenance, error) {
+	return generateSeeded(&Provenance{Type: TypeF, Seed: seed, Bits: bits})
+}
+
+// VerifyGeneration repeats the generation described by record, and checks
+// that it produces params. It returns nil if it does, ErrUnknownAlgorithm if
+// the record was produced by a different version of the generation procedure,
+// and ErrProvenanceMismatch otherwise.
+func VerifyGeneration(params *Params, record *Provenance) error {
+	if record.Algorithm != ProvenanceAlgorithm {
+		return ErrUnknownAlgorithm
+	}
+	if params.Fingerprint() != record.Fingerprint {
+		return ErrProvenanceMismatch
+	}
+	replay := *record
+	generated, result, err := generateSeeded(&replay)
+	if err != nil {
+		return err
+	}
+	if generated.String() != params.String() || result.Draws != record.Draws {
+		return ErrProvenanceMismatch
+	}
+	return nil
+}
+
+// personalization returns the DRBG personalization string for the record,
+// which binds the random stream to the algorithm and the arguments.
+func (record *Provenance) personalization() []byte {
+	return []byte(fmt.Sprintf("%s|type %s|rbits %d|qbits %d|bits %d|r %v",
+		ProvenanceAlgorithm, record.Type, record.RBits, record.QBits, record.Bits, record.R))
+}
+
+// generateSeeded generates parameters as described by record, and completes
+// the record.
+func generateSeeded(record *Provenance) (*Params, *Provenance, error) {
+	record.Algorithm = ProvenanceAlgorithm
+	drbg, err := NewDRBG(record.Seed, record.personalization())
+	if err != nil {
+		return nil, nil, err
+	}
+	source := &countingSource{source: drbg}
+
+	params, err := generateWithSource(source, record)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record.Draws = source.draws
+	record.Fingerprint = params.Fingerprint()
+	return params, record, nil
+}
+
+// generateWithSource runs the PBC generator described by record, drawing all
+// random numbers from source.
+func generateWithSource(source RandomSource, record *Provenance) (*Params, error) {
+	switch record.Type {
+	case TypeA, TypeE, TypeF:
+	case TypeA1:
+		if record.R == nil {
+			return nil, ErrIllegalNil
+		}
+	default:
+		return nil, ErrWrongParamsType
+	}
+
+	// The finalizer is only set once the generator has returned, since a
+	// partially initialized structure cannot be cleared
+	params := &Params{cptr: C.newParamStruct()}
+	withRandomSource(source, func() {
+		switch record.Type {
+		case TypeA:
+			C.pbc_param_init_a_gen(params.cptr, C.int(record.RBits), C.int(record.QBits))
+		case TypeA1:
+			mr := big2mpz(record.R)
+			defer mr.free()
+			C.pbc_param_init_a1_gen(params.cptr, &mr.i[0])
+		case TypeE:
+			C.pbc_param_init_e_gen(params.cptr, C.int(record.RBits), C.int(record.QBits))
+		case TypeF:
+			C.pbc_param_init_f_gen(params.cptr, C.int(record.Bits))
+		}
+	})
+	runtime.SetFinalizer(params, clearParams)
+	return params, nil
+}
@@ -0,0 +1,91 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGenerateSeeded(t *testing.T) {
+	previous := RandomProvider()
+	seed := bytes.Repeat([]byte{0x5a}, 32)
+	params, record, err := GenerateASeeded(seed, 160, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Algorithm != ProvenanceAlgorithm || record.Type != TypeA || record.Draws == 0 {
+		t.Errorf("unexpected provenance record %+v", record)
+	}
+	if record.Fingerprint != params.Fingerprint() {
+		t.Error("record has the wrong fingerprint")
+	}
+
+	again, _, err := GenerateASeeded(seed, 160, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != params.String() {
+		t.Error("same seed generated different parameters")
+	}
+	if err := VerifyGeneration(params, record); err != nil {
+		t.Errorf("verification failed: %v", err)
+	}
+
+	other, _, err := GenerateASeeded(append(seed[:31:31], 0x5b), 160, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.String() == params.String() {
+		t.Error("different seeds generated the same parameters")
+	}
+	if err := VerifyGeneration(other, record); err != ErrProvenanceMismatch {
+		t.Errorf("verifying the wrong parameters returned %v", err)
+	}
+
+	tampered := *record
+	tampered.QBits = 520
+	if err := VerifyGeneration(params, &tampered); err != ErrProvenanceMismatch {
+		t.Errorf("verifying a tampered record returned %v", err)
+	}
+	tampered = *record
+	tampered.Algorithm = "unknown"
+	if err := VerifyGeneration(params, &tampered); err != ErrUnknownAlgorithm {
+		t.Errorf("verifying an unknown algorithm returned %v", err)
+	}
+
+	if _, _, err := GenerateFSeeded(seed[:8], 160); err != ErrShortSeed {
+		t.Errorf("short seed returned %v", err)
+	}
+	if RandomProvider() != previous {
+		t.Error("random provider was not restored")
+	}
+
+	// A failing source must not remain installed for the thread
+	failing := NewReaderSource(failingReader{})
+	expectPanic(t, ErrEntropyFailure, func() { generateWithSource(failing, &Provenance{Type: TypeF, Bits: 160}) })
+	if RandomProvider() != previous {
+		t.Error("random provider was changed by a panic")
+	}
+	pairing := testPairing(t)
+	pairing.NewG1().Rand()
+}
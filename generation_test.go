@@ -0,0 +1,158 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestGenerateContext(t *testing.T) {
+	var reports []CMProgress
+	params, err := GenerateDContext(context.Background(), 9563, 160, 171, 500, func(p CMProgress) {
+		reports = append(reports, p)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Type() != TypeD {
+		t.Errorf("generated parameters of type %s", params.Type())
+	}
+	if len(reports) == 0 {
+		t.Fatal("no progress was reported")
+	}
+	last := reports[len(reports)-1]
+	if last.Discriminant != 9563 || last.Candidates != len(reports) || last.Rejected != last.Candidates-1 {
+		t.Errorf("unexpected progress report %+v", last)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GenerateGContext(ctx, 9563, 160, 171, 500, nil); err != context.Canceled {
+		t.Errorf("cancelled search returned %v", err)
+	}
+	if _, err := GenerateAContext(ctx, 160, 512, nil); err != context.Canceled {
+		t.Errorf("cancelled generation returned %v", err)
+	}
+
+	var draws []GenerationProgress
+	params, err = GenerateAContext(context.Background(), 20, 64, func(p GenerationProgress) {
+		draws = append(draws, p)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Type() != TypeA {
+		t.Errorf("generated parameters of type %s", params.Type())
+	}
+	if len(draws) == 0 || draws[len(draws)-1].Draws != len(draws) {
+		t.Errorf("unexpected progress reports %+v", draws)
+	}
+
+	// Cancel a generation while PBC is running, and check that it stops
+	// drawing random numbers
+	ctx, cancel = context.WithCancel(context.Background())
+	draws = nil
+	params, err = generateCancellable(ctx, &Provenance{Type: TypeA, RBits: 160, QBits: 512}, func(p GenerationProgress) {
+		draws = append(draws, p)
+		cancel()
+	})
+	if err != context.Canceled || params != nil {
+		t.Errorf("generation cancelled while running returned %v", err)
+	}
+	if len(draws) != 1 {
+		t.Errorf("%d random numbers were drawn after cancellation", len(draws)-1)
+	}
+	if _, err := GenerateA1Context(context.Background(), nil, nil); err != ErrIllegalNil {
+		t.Errorf("generation with a nil order returned %v", err)
+	}
+}
+
+func TestGenerateG(t *testing.T) {
+	// D = 43 has a Freeman curve over a 9-bit field
+	search := SearchMNT(TypeG, 43, 43, 100, nil)
+	if !search.Next() {
+		t.Fatalf("no Freeman curve was found: %v", search.Err())
+	}
+	c := search.Candidate()
+	params, err := GenerateG(43, uint32(c.R.BitLen()), uint32(c.Q.BitLen()), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Type() != TypeG || params.EmbeddingDegree() != 10 {
+		t.Errorf("generated parameters of type %s with k = %d", params.Type(), params.EmbeddingDegree())
+	}
+	if params.FieldCharacteristic().Cmp(c.Q) != 0 || params.Order().Cmp(c.R) != 0 {
+		t.Error("generated parameters do not match the first candidate")
+	}
+}
+
+func TestSearchMNT(t *testing.T) {
+	expected, err := GenerateD(9563, 160, 171, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedQ := expected.FieldCharacteristic()
+
+	search := SearchMNT(TypeD, 9560, 9570, 500, func(c *Candidate) bool {
+		return c.R.BitLen() >= 160 && c.Q.BitLen() >= 171
+	})
+	found := false
+	for search.Next() {
+		c := search.Candidate()
+		if c.D < 9560 || c.D > 9570 || !validDiscriminant(c.D) || c.K != 6 {
+			t.Errorf("unexpected candidate with D = %d, k = %d", c.D, c.K)
+		}
+		if new(big.Int).Mul(c.H, c.R).Cmp(c.N) != 0 {
+			t.Error("N != H * R")
+		}
+		if new(big.Int).Sub(new(big.Int).Add(c.Q, big.NewInt(1)), c.Trace).Cmp(c.N) != 0 {
+			t.Error("N != Q + 1 - Trace")
+		}
+		if c.Q.Cmp(expectedQ) == 0 {
+			found = true
+			params := c.Params()
+			if params.Type() != TypeD || params.Order().Cmp(c.R) != 0 {
+				t.Error("candidate parameters do not match the candidate")
+			}
+		}
+	}
+	if err := search.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("the curve found by GenerateD was not enumerated")
+	}
+
+	if search := SearchMNT(TypeA, 1, 10, 100, nil); search.Next() || search.Err() != ErrWrongParamsType {
+		t.Error("invalid kind was accepted")
+	}
+	for d, valid := range map[uint32]bool{
+		3: true, 4: true, 8: true, 20: true, 24: true, 9563: true,
+		0: false, 5: false, 6: false, 12: false, 16: false, 27: false, 28: false, 36: false, 72: false,
+	} {
+		if validDiscriminant(d) != valid {
+			t.Errorf("validDiscriminant(%d) != %t", d, valid)
+		}
+	}
+}
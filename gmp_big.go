@@ -30,8 +30,20 @@ mpz_t* newMpzT() {
 	mpz_init(*x);
 	return x;
 }
+size_t mpzTSize(mpz_t* x) {
+	return sizeof(mpz_t) + (*x)->_mp_alloc * sizeof(mp_limb_t);
+}
+// clearSecretMpz wipes the limbs of x before clearing it. The volatile pointer
+// prevents the compiler from removing the stores.
+void clearSecretMpz(mpz_t x) {
+	volatile mp_limb_t* limbs = x->_mp_d;
+	int i;
+	for (i = 0; i < x->_mp_alloc; i++) limbs[i] = 0;
+	mpz_clear(x);
+}
 void freeMpzT(mpz_t* x) {
-	mpz_clear(*x);
+	// The integer may hold a secret
+	clearSecretMpz(*x);
 	free(x);
 }
 */
@@ -44,19 +56,30 @@ import (
 )
 
 type mpz struct {
-	i *C.mpz_t
+	i    *C.mpz_t
+	size int64 // the size recorded in the allocation statistics
 }
 
 var wordSize C.size_t
 var bitsPerWord C.size_t
 
 func clearMpz(x *mpz) {
+	recordFree(MpzObject, noField, x.size)
 	C.freeMpzT(x.i)
 }
 
+// free releases the memory used by x immediately instead of waiting for the
+// garbage collector. x must not be used afterwards.
+func (x *mpz) free() {
+	runtime.SetFinalizer(x, nil)
+	clearMpz(x)
+}
+
 func newMpz() *mpz {
 	out := &mpz{}
 	out.i = C.newMpzT()
+	out.size = int64(C.mpzTSize(out.i))
+	recordAlloc(MpzObject, noField, out.size)
 	runtime.SetFinalizer(out, clearMpz)
 	return out
 }
@@ -0,0 +1,511 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+/*
+#include <pbc/pbc.h>
+*/
+import "C"
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"sync"
+)
+
+// hashSecurityBits is the target security level k of RFC 9380. It determines
+// how many bytes are reduced into each field element.
+const hashSecurityBits = 128
+
+// cofactorProbeDST separates the hash used to identify the order of twisted
+// curves from all application hashes.
+var cofactorProbeDST = []byte("PBC-GO-COFACTOR-PROBE")
+
+// expandMessageXMD implements expand_message_xmd from RFC 9380, section 5.3.1,
+// using SHA-256.
+func expandMessageXMD(msg, dst []byte, length int) []byte {
+	const blockSize = 64
+	if len(dst) > 255 {
+		h := sha256.New()
+		h.Write([]byte("H2C-OVERSIZE-DST-"))
+		h.Write(dst)
+		dst = h.Sum(nil)
+	}
+	ell := (length + sha256.Size - 1) / sha256.Size
+	if ell > 255 || length > 65535 {
+		panic(ErrHashFailure)
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := sha256.New()
+	h.Write(make([]byte, blockSize))
+	h.Write(msg)
+	h.Write([]byte{byte(length >> 8), byte(length), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	uniform := make([]byte, 0, ell*sha256.Size)
+	uniform = append(uniform, bi...)
+	for i := 2; i <= ell; i++ {
+		xored := make([]byte, sha256.Size)
+		for j := range xored {
+			xored[j] = b0[j] ^ bi[j]
+		}
+		h.Reset()
+		h.Write(xored)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		uniform = append(uniform, bi...)
+	}
+	return uniform[:length]
+}
+
+// coefficients returns views of the base field coefficients of x, which may
+// be an element of the base field or of an extension field.
+func coefficients(x *Element) []*Element {
+	n := x.Len()
+	if n == 0 {
+		return []*Element{x}
+	}
+	var result []*Element
+	for i := 0; i < n; i++ {
+		result = append(result, coefficients(x.Item(i))...)
+	}
+	return result
+}
+
+// curveMap holds the coefficients of the curve y^2 = x^3 + a*x + b underlying
+// G1 or G2, along with the constants of the Shallue-van de Woestijne map
+// (RFC 9380, section 6.6.1) to the curve. The curve coefficients are recovered
+// from random points, so the same code handles both the base curves and the
+// twists used for G2, whose coordinates lie in extension fields. The constants
+// of the map and the cofactor are only computed when hashing is first needed.
+type curveMap struct {
+	field    Field
+	template *Element // an unchecked element of the coordinate field
+	q        *big.Int
+	degree   int
+	a, b, z  *Element
+	c1, c2   *Element
+	c3, c4   *Element
+	cofactor *big.Int
+}
+
+// curveMapper binds a curveMap to its pairing while it is in use. The map
+// itself must not refer to the pairing: it is cached in the pairing, and a
+// reference cycle through a finalized object would never be collected.
+type curveMapper struct {
+	*curveMap
+	pairing *Pairing
+}
+
+type curveMapCache struct {
+	once     sync.Once
+	m        *curveMap
+	hashOnce sync.Once
+	hashErr  error
+}
+
+// curveMap returns the curve underlying field, which must be G1 or G2. Only
+// the curve coefficients of the result are initialized.
+func (pairing *Pairing) curveMap(field Field) *curveMap {
+	if field == G2 && pairing.IsSymmetric() {
+		field = G1
+	}
+	cache := &pairing.curveMaps[field]
+	cache.once.Do(func() {
+		cache.m = newCurveMap(pairing, field)
+	})
+	return cache.m
+}
+
+// hashMap returns the map to the curve underlying field, which must be G1 or
+// G2, with the constants needed for hashing initialized. It returns
+// ErrNoHashToCurve if the cofactor of the curve cannot be determined.
+func (pairing *Pairing) hashMap(field Field) (*curveMap, error) {
+	m := pairing.curveMap(field)
+	if field == G2 && pairing.IsSymmetric() {
+		field = G1
+	}
+	cache := &pairing.curveMaps[field]
+	cache.hashOnce.Do(func() {
+		cache.hashErr = curveMapper{m, pairing}.prepareHash()
+	})
+	if cache.hashErr != nil {
+		return nil, cache.hashErr
+	}
+	return m, nil
+}
+
+func randomPoint(pairing *Pairing, field Field) *Element {
+	p := makeUncheckedElement(pairing, true, field)
+	for p.Rand().Is0() {
+	}
+	return p
+}
+
+func newCurveMap(pairing *Pairing, field Field) *curveMap {
+	p1 := randomPoint(pairing, field)
+	p2 := randomPoint(pairing, field)
+	for p1.Item(0).Equals(p2.Item(0)) {
+		p2 = randomPoint(pairing, field)
+	}
+	x1, y1 := p1.Item(0), p1.Item(1)
+	x2, y2 := p2.Item(0), p2.Item(1)
+
+	m := curveMapper{&curveMap{
+		field:    field,
+		template: x1.NewFieldElement(),
+		q:        pairing.Params().FieldCharacteristic(),
+	}, pairing}
+	m.degree = len(coefficients(m.template))
+
+	// Solve y^2 - x^3 = a*x + b using the two points
+	e1 := m.newElement().Square(y1)
+	e1.Sub(e1, m.newElement().Square(x1).ThenMul(x1))
+	e2 := m.newElement().Square(y2)
+	e2.Sub(e2, m.newElement().Square(x2).ThenMul(x2))
+	m.a = m.newElement().Sub(e1, e2)
+	m.a.Div(m.a, m.newElement().Sub(x1, x2))
+	m.b = m.newElement().Mul(m.a, x1)
+	m.b.Sub(e1, m.b)
+
+	for _, x := range m.constants() {
+		detachElement(x)
+	}
+	return m.curveMap
+}
+
+// prepareHash computes the constants of the map and the cofactor of the
+// curve.
+func (m curveMapper) prepareHash() error {
+	m.findZ()
+
+	// h = 3*Z^2 + 4*A
+	h := m.newElement().Square(m.z).ThenMulInt32(3)
+	h.Add(h, m.newElement().MulInt32(m.a, 4))
+	m.c1 = m.g(m.z)
+	m.c2 = m.newElement().Neg(m.z).ThenHalve()
+	m.c3 = m.sqrt(m.newElement().Mul(m.c1, h).ThenNeg())
+	if m.sgn0(m.c3) == 1 {
+		m.c3.ThenNeg()
+	}
+	m.c4 = m.newElement().MulInt32(m.c1, -4).ThenDiv(h)
+
+	err := m.findCofactor()
+	for _, x := range []*Element{m.z, m.c1, m.c2, m.c3, m.c4} {
+		detachElement(x)
+	}
+	return err
+}
+
+// constants returns the elements held by the map that have been initialized.
+func (m *curveMap) constants() []*Element {
+	var result []*Element
+	for _, x := range []*Element{m.template, m.a, m.b, m.z, m.c1, m.c2, m.c3, m.c4} {
+		if x != nil {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// free releases the constants of the map. It must be called before the
+// pairing is freed.
+func (m *curveMap) free() {
+	for _, x := range m.constants() {
+		freeDetachedElement(x)
+	}
+}
+
+func (m curveMapper) newElement() *Element {
+	x := makeUncheckedElement(m.pairing, false, noField)
+	C.element_init_same_as(x.cptr, m.template.cptr)
+	x.recordAlloc()
+	return x
+}
+
+// sqrt returns a square root of x, which must be a square.
+func (m curveMapper) sqrt(x *Element) *Element {
+	out := m.newElement()
+	C.element_sqrt(out.cptr, x.cptr)
+	return out
+}
+
+// g returns x^3 + a*x + b.
+func (m curveMapper) g(x *Element) *Element {
+	out := m.newElement().Square(x)
+	out.Add(out, m.a).ThenMul(x)
+	return out.ThenAdd(m.b)
+}
+
+// findZ implements find_z_svdw from RFC 9380, appendix H.1.
+func (m curveMapper) findZ() {
+	three := m.newElement().SetInt32(3)
+	four := m.newElement().SetInt32(4)
+	for ctr := int32(1); ; ctr++ {
+		for _, candidate := range []int32{ctr, -ctr} {
+			z := m.newElement().SetInt32(candidate)
+			gz := m.g(z)
+			if gz.Is0() {
+				continue
+			}
+			h := m.newElement().Square(z).ThenMul(three)
+			h.Add(h, m.newElement().Mul(four, m.a)).ThenNeg()
+			if h.Is0() {
+				continue
+			}
+			h.Div(h, m.newElement().Mul(four, gz))
+			if !h.IsSquare() {
+				continue
+			}
+			if gz.IsSquare() || m.g(m.newElement().Neg(z).ThenHalve()).IsSquare() {
+				m.z = z
+				return
+			}
+		}
+	}
+}
+
+// sgn0 implements the sgn0 function from RFC 9380, section 4.1.
+func (m curveMapper) sgn0(x *Element) int {
+	sign, zero := 0, 1
+	for _, c := range coefficients(x) {
+		i := c.BigInt()
+		signI := int(i.Bit(0))
+		zeroI := 0
+		if i.Sign() == 0 {
+			zeroI = 1
+		}
+		sign |= zero & signI
+		zero &= zeroI
+	}
+	return sign
+}
+
+// hashToField implements hash_to_field from RFC 9380, section 5.2.
+func (m curveMapper) hashToField(msg, dst []byte, count int) []*Element {
+	length := (m.q.BitLen() + hashSecurityBits + 7) / 8
+	uniform := expandMessageXMD(msg, dst, count*m.degree*length)
+	result := make([]*Element, count)
+	for i := range result {
+		result[i] = m.newElement()
+		for j, c := range coefficients(result[i]) {
+			offset := length * (j + i*m.degree)
+			e := new(big.Int).SetBytes(uniform[offset : offset+length])
+			c.SetBig(e.Mod(e, m.q))
+		}
+	}
+	return result
+}
+
+// mapToCurve implements map_to_curve_svdw from RFC 9380, section 6.6.1, and
+// returns an unchecked point on the curve.
+func (m curveMapper) mapToCurve(u *Element) *Element {
+	tv1 := m.newElement().Square(u).ThenMul(m.c1)
+	tv2 := m.newElement().Set1().ThenAdd(tv1)
+	tv1.Sub(m.newElement().Set1(), tv1)
+	tv3 := m.newElement().Mul(tv1, tv2)
+	if !tv3.Is0() {
+		tv3.ThenInvert()
+	}
+	tv4 := m.newElement().Mul(u, tv1).ThenMul(tv3).ThenMul(m.c3)
+	x1 := m.newElement().Sub(m.c2, tv4)
+	x2 := m.newElement().Add(m.c2, tv4)
+	x := x1
+	if !m.g(x1).IsSquare() {
+		x = x2
+		if !m.g(x2).IsSquare() {
+			x = m.newElement().Square(tv2).ThenMul(tv3).ThenSquare().ThenMul(m.c4).ThenAdd(m.z)
+		}
+	}
+	y := m.sqrt(m.g(x))
+	if m.sgn0(u) != m.sgn0(y) {
+		y.ThenNeg()
+	}
+
+	p := makeUncheckedElement(m.pairing, true, m.field)
+	p.SetBytes(append(x.Bytes(), y.Bytes()...))
+	if p.Is0() {
+		// PBC rejected the point, so the recovered curve is wrong
+		panic(ErrInternal)
+	}
+	return p
+}
+
+// findCofactor determines the cofactor used to clear mapped points into the
+// subgroup of order r. For G1, this is the cofactor from the parameters. For
+// the twisted curves underlying G2, the order of the twist is not stored in
+// the parameters, so the possible twist orders are derived from the trace of
+// Frobenius and the correct one is identified using a mapped point.
+func (m curveMapper) findCofactor() error {
+	params := m.pairing.Params()
+	if m.field == G1 {
+		m.cofactor = params.Cofactor()
+		return nil
+	}
+
+	var orders []*big.Int
+	q := m.q
+	one := big.NewInt(1)
+	switch params.Type() {
+	case TypeD, TypeG:
+		// Quadratic twist over F_q^(k/2): q^d + 1 -/+ t_d
+		_, values := params.values()
+		d := values.integer("k", new(error)) / 2
+		n := values.bigInt("n", new(error))
+		t := new(big.Int).Add(q, one)
+		t.Sub(t, n)
+		td := traceOverExtension(t, q, d)
+		qd := new(big.Int).Exp(q, big.NewInt(int64(d)), nil)
+		qd.Add(qd, one)
+		orders = append(orders, new(big.Int).Sub(qd, td), new(big.Int).Add(qd, td))
+	case TypeF:
+		// Sextic twists over F_q^2: q^2 + 1 - (+/-t_2 +/- 3f)/2, where
+		// t_2^2 - 4q^2 = -3f^2
+		t := new(big.Int).Add(q, one)
+		t.Sub(t, params.Order())
+		t2 := traceOverExtension(t, q, 2)
+		q2 := new(big.Int).Mul(q, q)
+		f := new(big.Int).Lsh(q2, 2)
+		f.Sub(f, new(big.Int).Mul(t2, t2))
+		f.Div(f, big.NewInt(3))
+		f.Sqrt(f)
+		f.Mul(f, big.NewInt(3))
+		q2.Add(q2, one)
+		orders = append(orders, new(big.Int).Sub(q2, t2), new(big.Int).Add(q2, t2))
+		for _, s := range []*big.Int{t2, new(big.Int).Neg(t2)} {
+			for _, g := range []*big.Int{f, new(big.Int).Neg(f)} {
+				x := new(big.Int).Add(s, g)
+				x.Rsh(x, 1)
+				orders = append(orders, new(big.Int).Sub(q2, x))
+			}
+		}
+	}
+
+	r := m.pairing.Order()
+	probe := m.mapToCurve(m.hashToField(nil, cofactorProbeDST, 1)[0])
+	x := makeUncheckedElement(m.pairing, true, m.field)
+	for _, order := range orders {
+		cofactor, rem := new(big.Int).QuoRem(order, r, new(big.Int))
+		if rem.Sign() != 0 {
+			continue
+		}
+		// Use PBC directly, since PowBig may assume that the order divides r
+		o := big2mpz(order)
+		C.element_pow_mpz(x.cptr, probe.cptr, &o.i[0])
+		o.free()
+		if x.Is0() {
+			m.cofactor = cofactor
+			return nil
+		}
+	}
+	return ErrNoHashToCurve
+}
+
+// traceOverExtension returns the trace of Frobenius over F_q^d given the trace
+// t over F_q, using t_(i+1) = t*t_i - q*t_(i-1).
+func traceOverExtension(t, q *big.Int, d int) *big.Int {
+	prev, cur := big.NewInt(2), new(big.Int).Set(t)
+	for i := 1; i < d; i++ {
+		next := new(big.Int).Mul(t, cur)
+		next.Sub(next, new(big.Int).Mul(q, prev))
+		prev, cur = cur, next
+	}
+	return cur
+}
+
+// HashToGroup hashes msg to an element of G1 or G2, and returns the element as
+// a new checked element. See SetFromHashToGroup for details.
+func (pairing *Pairing) HashToGroup(field Field, msg, dst []byte) *Element {
+	if field != G1 && field != G2 {
+		panic(ErrIllegalOp)
+	}
+	el := makeCheckedElement(pairing, field, pairing.fieldPtr(field))
+	return el.SetFromHashToGroup(msg, dst)
+}
+
+// SetFromHashToGroup sets el to the hash of msg, and returns el. Unlike
+// SetFromHash, this method implements the random oracle construction
+// hash_to_curve from RFC 9380: msg is expanded with expand_message_xmd using
+// SHA-256 and the domain separation tag dst, the result is reduced to two
+// field elements with a security margin of 128 bits, each field element is
+// mapped to the curve with the Shallue-van de Woestijne map, and the sum of
+// the two points is multiplied by the cofactor of the curve. The output is
+// therefore indifferentiable from a random oracle into the group.
+//
+// Every application must use its own domain separation tag; RFC 9380 gives
+// recommendations for choosing one. The first call for each group of a
+// pairing precomputes the constants of the map.
+//
+// Requirements:
+// el must belong to the pairing's G1 or G2 group.
+func (el *Element) SetFromHashToGroup(msg, dst []byte) *Element {
+	if el.field != G1 && el.field != G2 {
+		panic(ErrIllegalOp)
+	}
+	cm, err := el.pairing.hashMap(el.field)
+	if err != nil {
+		panic(err)
+	}
+	m := curveMapper{cm, el.pairing}
+	u := m.hashToField(msg, dst, 2)
+	q0 := m.mapToCurve(u[0])
+	q1 := m.mapToCurve(u[1])
+	q0.Add(q0, q1)
+	cofactor := big2mpz(m.cofactor)
+	C.element_pow_mpz(el.cptr, q0.cptr, &cofactor.i[0])
+	cofactor.free()
+	return el
+}
+
+// HashToZr hashes the messages to a uniformly distributed element of Zr, and
+// returns the element as a new checked element. It is suitable for deriving
+// Fiat-Shamir challenges and other scalars.
+//
+// The messages are encoded unambiguously by prefixing each with its length as
+// a 64-bit big-endian integer, so that ("ab", "c") and ("a", "bc") hash to
+// different values. The encoding is expanded with expand_message_xmd from RFC
+// 9380 using SHA-256 and the domain separation tag dst, producing 128 bits
+// more than the size of r. The result is reduced modulo r, so its bias is
+// negligible regardless of the size of r.
+func (pairing *Pairing) HashToZr(dst []byte, msg ...[]byte) *Element {
+	var encoded []byte
+	for _, m := range msg {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(m)))
+		encoded = append(encoded, length[:]...)
+		encoded = append(encoded, m...)
+	}
+	r := pairing.Order()
+	uniform := expandMessageXMD(encoded, dst, (r.BitLen()+hashSecurityBits+7)/8)
+	i := new(big.Int).SetBytes(uniform)
+	return pairing.NewZr().SetBig(i.Mod(i, r))
+}
@@ -0,0 +1,201 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+/*
+#include <stdint.h>
+#include <pbc/pbc.h>
+*/
+import "C"
+
+import (
+	"math/big"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// Candidate describes an MNT curve found by SearchMNT. The curve is defined
+// over F_q and has N = Q + 1 - Trace points, where N = H * R and R is prime.
+// Computing the full parameters requires finding the curve coefficients, which
+// is relatively slow, so it is deferred until Params is called.
+type Candidate struct {
+	Type  PairingType // TypeD or TypeG
+	D     uint32      // the CM discriminant
+	K     int         // the embedding degree: 6 for type D, or 10 for type G
+	Q     *big.Int
+	N     *big.Int
+	H     *big.Int
+	R     *big.Int
+	Trace *big.Int
+}
+
+// Params generates the pairing parameters for the candidate curve.
+func (c *Candidate) Params() *Params {
+	params := makeParams()
+	q, n, h, r := big2mpz(c.Q), big2mpz(c.N), big2mpz(c.H), big2mpz(c.R)
+	typeD := C.int(0)
+	if c.Type == TypeD {
+		typeD = 1
+	}
+	C.paramsFromCM(params.cptr, typeD, &q.i[0], &n.i[0], &h.i[0], &r.i[0], C.int(c.D), C.int(c.K))
+	for _, x := range []*mpz{q, n, h, r} {
+		x.free()
+	}
+	return params
+}
+
+// MNTSearch enumerates the MNT curves with discriminants in a given range,
+// unlike GenerateD and GenerateG, which stop at the first suitable curve. It
+// is created by SearchMNT and used like bufio.Scanner:
+//
+// 	search := pbc.SearchMNT(pbc.TypeD, 1, 100000, 500, func(c *pbc.Candidate) bool {
+// 		return c.R.BitLen() >= 160 && c.Q.BitLen() <= 180
+// 	})
+// 	for search.Next() {
+// 		fmt.Println(search.Candidate().D, search.Candidate().R)
+// 	}
+// 	if err := search.Err(); err != nil {
+// 		// handle the error
+// 	}
+//
+// The discriminants are searched in increasing order, one at a time, so
+// candidates are produced incrementally. MNTSearch is not safe for concurrent
+// use.
+type MNTSearch struct {
+	kind     PairingType
+	d, dmax  uint32
+	bitlimit uint32
+	filter   func(*Candidate) bool
+
+	pending []*Candidate
+	current *Candidate
+	done    bool
+	err     error
+}
+
+// SearchMNT starts a search for MNT curves of the given kind, which must be
+// TypeD (embedding degree 6) or TypeG (embedding degree 10), over the
+// discriminants in [dmin,dmax]. Discriminants that are not valid for the CM
+// method are skipped. As in GenerateD, bitlimit caps the size of the group
+// order. If filter is non-nil, only the candidates for which it returns true
+// are produced.
+func SearchMNT(kind PairingType, dmin, dmax, bitlimit uint32, filter func(*Candidate) bool) *MNTSearch {
+	search := &MNTSearch{
+		kind:     kind,
+		d:        dmin,
+		dmax:     dmax,
+		bitlimit: bitlimit,
+		filter:   filter,
+	}
+	if kind != TypeD && kind != TypeG {
+		search.err = ErrWrongParamsType
+	}
+	return search
+}
+
+// Next advances the search to the next candidate, which is then available
+// through Candidate. It returns false when the search is complete or an error
+// occurs.
+func (search *MNTSearch) Next() bool {
+	search.current = nil
+	for search.err == nil && len(search.pending) == 0 {
+		if search.done || search.d > search.dmax {
+			return false
+		}
+		d := search.d
+		if d == search.dmax {
+			search.done = true
+		} else {
+			search.d++
+		}
+		if validDiscriminant(d) {
+			search.searchDiscriminant(d)
+		}
+	}
+	if search.err != nil {
+		return false
+	}
+	search.current = search.pending[0]
+	search.pending = search.pending[1:]
+	return true
+}
+
+// Candidate returns the most recent candidate produced by Next.
+func (search *MNTSearch) Candidate() *Candidate { return search.current }
+
+// Err returns the error, if any, that stopped the search.
+func (search *MNTSearch) Err() error { return search.err }
+
+func (search *MNTSearch) searchDiscriminant(d uint32) {
+	handle := cgo.NewHandle(search)
+	defer handle.Delete()
+	h := C.uintptr_t(handle)
+	if search.kind == TypeD {
+		C.pbc_cm_search_d((*[0]byte)(C.collectCM), unsafe.Pointer(&h), C.uint(d), C.uint(search.bitlimit))
+	} else {
+		C.pbc_cm_search_g((*[0]byte)(C.collectCM), unsafe.Pointer(&h), C.uint(d), C.uint(search.bitlimit))
+	}
+}
+
+//export goCMCollect
+func goCMCollect(handle C.uintptr_t, cm *C.struct_pbc_cm_s) C.int {
+	search := cgo.Handle(handle).Value().(*MNTSearch)
+	c := &Candidate{
+		Type: search.kind,
+		D:    uint32(cm.D),
+		K:    int(cm.k),
+		Q:    mpz2big(&mpz{i: &cm.q}),
+		N:    mpz2big(&mpz{i: &cm.n}),
+		H:    mpz2big(&mpz{i: &cm.h}),
+		R:    mpz2big(&mpz{i: &cm.r}),
+	}
+	c.Trace = new(big.Int).Add(c.Q, big.NewInt(1))
+	c.Trace.Sub(c.Trace, c.N)
+	if search.filter == nil || search.filter(c) {
+		search.pending = append(search.pending, c)
+	}
+	return 0
+}
+
+// validDiscriminant returns true if -d is a fundamental discriminant, as
+// required by the CM method: either d = 3 mod 4 and d is squarefree, or d = 4m
+// where -m = 2 or 3 mod 4 (that is, m = 1 or 2 mod 4) and m is squarefree.
+func validDiscriminant(d uint32) bool {
+	switch {
+	case d == 0:
+		return false
+	case d%4 == 0:
+		d /= 4
+		if d%4 != 1 && d%4 != 2 {
+			return false
+		}
+	case d%4 != 3:
+		return false
+	}
+	// d is not divisible by 4, so only odd squares remain to be checked
+	for p := uint64(3); p*p <= uint64(d); p += 2 {
+		if uint64(d)%(p*p) == 0 {
+			return false
+		}
+	}
+	return true
+}
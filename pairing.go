@@ -35,7 +35,9 @@ import "C"
 import (
 	"bytes"
 	"io"
+	"math/big"
 	"runtime"
+	"sync"
 )
 
 // Field denotes the various possible algebraic structures associated with a
@@ -56,26 +58,61 @@ const (
 // created in G1, G2, GT, or Zr. Additionally, elements can be checked or
 // unchecked. See the Element type for more details.
 type Pairing struct {
-	params *Params // Prevents garbage collection
-	cptr *C.struct_pairing_s
+	params       *Params
+	paramsString string
+	fingerprint  Fingerprint
+	curveMaps    [2]curveMapCache
+	cptr         *C.struct_pairing_s
+
+	// mu guards the fields below. live counts the native objects (elements,
+	// powers, and pairers) that refer to cptr. If the pairing is closed while
+	// such objects exist, freeing cptr is deferred until the last one is
+	// released.
+	mu     sync.Mutex
+	live   int
+	closed bool
 }
 
-// NewPairing instantiates a pairing from a set of parameters.
+// NewPairing instantiates a pairing from a set of parameters. The pairing
+// retains a reference to params, which can later be retrieved with the Params
+// method.
 func NewPairing(params *Params) *Pairing {
 	pairing := makePairing(params)
 	C.pairing_init_pbc_param(pairing.cptr, params.cptr)
+	pairing.paramsString = params.String()
+	pairing.fingerprint = fingerprintOf(pairing.paramsString)
+	return pairing
+}
 
-	// pairing.params must point to params during the C call. Otherwise, the
-	// garbage collector might free params.cptr (through the Params finalizer)
-	// while pairing_init_pbc_param is still running! However, this function no
-	// longer requires access to the parameters once control has returned. We
-	// set pairing.params to nil to indicate that we no longer care if params
-	// is garbage collected. If the caller still needs params (e.g., to
-	// initialize more pairings), the garbage collector will know not to free
-	// params even though pairing.params does not point to it.
-	pairing.params = nil
+// PairingOption configures a pairing created by NewPairingWithOptions.
+type PairingOption func(*pairingOptions)
 
-	return pairing
+type pairingOptions struct {
+	minimumSecurity int
+}
+
+// MinimumSecurity returns an option that causes NewPairingWithOptions to
+// refuse parameters whose estimated security is less than bits. See
+// Params.SecurityEstimate for how the security is estimated.
+func MinimumSecurity(bits int) PairingOption {
+	return func(options *pairingOptions) { options.minimumSecurity = bits }
+}
+
+// NewPairingWithOptions is like NewPairing, but applies the given options. It
+// returns ErrInsecureParams if the parameters do not meet the security level
+// required by MinimumSecurity.
+//
+// For example:
+// 	pairing, err := pbc.NewPairingWithOptions(params, pbc.MinimumSecurity(80))
+func NewPairingWithOptions(params *Params, opts ...PairingOption) (*Pairing, error) {
+	var options pairingOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.minimumSecurity > 0 && params.SecurityEstimate().Bits() < options.minimumSecurity {
+		return nil, ErrInsecureParams
+	}
+	return NewPairing(params), nil
 }
 
 // NewPairingFromReader loads pairing parameters from a Reader and instantiates
@@ -96,69 +133,139 @@ func NewPairingFromString(params string) (*Pairing, error) {
 	return NewPairing(p), nil
 }
 
+// Params returns the parameters that were used to instantiate the pairing. If
+// those parameters have been closed, a new copy is created from
+// ParamsString().
+func (pairing *Pairing) Params() *Params {
+	pairing.mu.Lock()
+	defer pairing.mu.Unlock()
+	if pairing.params.cptr == nil {
+		pairing.params, _ = NewParamsFromString(pairing.paramsString)
+	}
+	return pairing.params
+}
+
+// ParamsString returns the parameters of the pairing in the PBC text format.
+// The string is computed once when the pairing is created, so it is stable
+// for the lifetime of the pairing and is suitable for publication to peers or
+// for fingerprinting.
+func (pairing *Pairing) ParamsString() string { return pairing.paramsString }
+
+// Order returns r, the order of G1, G2, and GT. This is also the order of the
+// integer field Zr.
+func (pairing *Pairing) Order() *big.Int {
+	pairing.ensureOpen()
+	return mpz2big(&mpz{i: &pairing.cptr.r})
+}
+
+// G1Cofactor returns the cofactor h of G1. The curve over the base field has
+// h * r points, of which G1 is the subgroup of order r.
+func (pairing *Pairing) G1Cofactor() *big.Int {
+	return pairing.Params().Cofactor()
+}
+
+// FieldOrder returns the number of elements in the given algebraic structure.
+// For G1, G2, GT, and Zr, this is r (or n for type A1 pairings).
+func (pairing *Pairing) FieldOrder(field Field) *big.Int {
+	return mpz2big(&mpz{i: &pairing.fieldPtr(field).order})
+}
+
+// fieldPtr returns the PBC field corresponding to field.
+func (pairing *Pairing) fieldPtr(field Field) *C.struct_field_s {
+	pairing.ensureOpen()
+	switch field {
+	case G1:
+		return pairing.cptr.G1
+	case G2:
+		return pairing.cptr.G2
+	case GT:
+		return &pairing.cptr.GT[0]
+	case Zr:
+		return &pairing.cptr.Zr[0]
+	}
+	panic(ErrUnknownField)
+}
+
+// ensureOpen panics with ErrClosed if the native memory of the pairing has
+// been freed.
+func (pairing *Pairing) ensureOpen() {
+	if pairing.cptr == nil {
+		panic(ErrClosed)
+	}
+}
+
 // IsSymmetric returns true if G1 == G2 for this pairing.
 func (pairing *Pairing) IsSymmetric() bool {
+	pairing.ensureOpen()
 	return C.pairing_is_symmetric(pairing.cptr) != 0
 }
 
 // G1Length returns the size of elements in G1, in bytes.
 func (pairing *Pairing) G1Length() uint {
+	pairing.ensureOpen()
 	return uint(C.pairing_length_in_bytes_G1(pairing.cptr))
 }
 
 // G1XLength returns the size of X coordinates of elements in G1, in bytes.
 func (pairing *Pairing) G1XLength() uint {
+	pairing.ensureOpen()
 	return uint(C.pairing_length_in_bytes_x_only_G1(pairing.cptr))
 }
 
 // G1CompressedLength returns the size of compressed elements in G1, in bytes.
 func (pairing *Pairing) G1CompressedLength() uint {
+	pairing.ensureOpen()
 	return uint(C.pairing_length_in_bytes_compressed_G1(pairing.cptr))
 }
 
 // G2Length returns the size of elements in G2, in bytes.
 func (pairing *Pairing) G2Length() uint {
+	pairing.ensureOpen()
 	return uint(C.pairing_length_in_bytes_G2(pairing.cptr))
 }
 
 // G2XLength returns the size of X coordinates of elements in G2, in bytes.
 func (pairing *Pairing) G2XLength() uint {
+	pairing.ensureOpen()
 	return uint(C.pairing_length_in_bytes_x_only_G2(pairing.cptr))
 }
 
 // G2CompressedLength returns the size of compressed elements in G2, in bytes.
 func (pairing *Pairing) G2CompressedLength() uint {
+	pairing.ensureOpen()
 	return uint(C.pairing_length_in_bytes_compressed_G2(pairing.cptr))
 }
 
 // GTLength returns the size of elements in GT, in bytes.
 func (pairing *Pairing) GTLength() uint {
+	pairing.ensureOpen()
 	return uint(C.pairing_length_in_bytes_GT(pairing.cptr))
 }
 
 // ZrLength returns the size of elements in Zr, in bytes.
 func (pairing *Pairing) ZrLength() uint {
+	pairing.ensureOpen()
 	return uint(C.pairing_length_in_bytes_Zr(pairing.cptr))
 }
 
 // NewG1 creates a new checked element in G1.
 func (pairing *Pairing) NewG1() *Element {
-	return makeCheckedElement(pairing, G1, pairing.cptr.G1)
+	return makeCheckedElement(pairing, G1, pairing.fieldPtr(G1))
 }
 
 // NewG2 creates a new checked element in G2.
 func (pairing *Pairing) NewG2() *Element {
-	return makeCheckedElement(pairing, G2, pairing.cptr.G2)
+	return makeCheckedElement(pairing, G2, pairing.fieldPtr(G2))
 }
 
 // NewGT creates a new checked element in GT.
 func (pairing *Pairing) NewGT() *Element {
-	return makeCheckedElement(pairing, GT, &pairing.cptr.GT[0])
+	return makeCheckedElement(pairing, GT, pairing.fieldPtr(GT))
 }
 
 // NewZr creates a new checked element in Zr.
 func (pairing *Pairing) NewZr() *Element {
-	return makeCheckedElement(pairing, Zr, &pairing.cptr.Zr[0])
+	return makeCheckedElement(pairing, Zr, pairing.fieldPtr(Zr))
 }
 
 // NewUncheckedElement creates a new unchecked element in the target field.
@@ -169,14 +276,69 @@ func (pairing *Pairing) NewUncheckedElement(field Field) *Element {
 	return makeUncheckedElement(pairing, true, field)
 }
 
+// Close frees the native memory used by the pairing. Go does not account for
+// this memory when deciding when to collect garbage, so applications that
+// create many pairings should close them explicitly instead of relying on the
+// garbage collector. Close is idempotent. Once closed, the pairing cannot be
+// used to create new elements, and it is removed from the registry used by
+// RegisterPairing.
+//
+// Elements, Powers, and Pairers that were created from the pairing remain
+// usable after Close; the memory is released once all of them have been
+// closed or garbage collected. After that, methods that query the pairing,
+// such as Order or G1Length, panic with ErrClosed.
+func (pairing *Pairing) Close() {
+	unregisterClosed(pairing)
+	pairing.mu.Lock()
+	defer pairing.mu.Unlock()
+	if pairing.closed {
+		return
+	}
+	pairing.closed = true
+	runtime.SetFinalizer(pairing, nil)
+	if pairing.live == 0 {
+		pairing.free()
+	}
+}
+
+// retain records a new native object that depends on the pairing.
+func (pairing *Pairing) retain() {
+	pairing.mu.Lock()
+	defer pairing.mu.Unlock()
+	if pairing.closed {
+		panic(ErrClosed)
+	}
+	pairing.live++
+}
+
+// release records that a native object depending on the pairing was freed.
+func (pairing *Pairing) release() {
+	pairing.mu.Lock()
+	defer pairing.mu.Unlock()
+	pairing.live--
+	if pairing.closed && pairing.live == 0 {
+		pairing.free()
+	}
+}
+
+func (pairing *Pairing) free() {
+	clearPairing(pairing)
+	pairing.cptr = nil
+}
+
 func clearPairing(pairing *Pairing) {
+	for i := range pairing.curveMaps {
+		if m := pairing.curveMaps[i].m; m != nil {
+			m.free()
+		}
+	}
 	C.freePairingStruct(pairing.cptr)
 }
 
 func makePairing(params *Params) *Pairing {
 	pairing := &Pairing{
 		params: params,
-		cptr: C.newPairingStruct(),
+		cptr:   C.newPairingStruct(),
 	}
 	runtime.SetFinalizer(pairing, clearPairing)
 	return pairing
@@ -60,7 +60,9 @@ import (
 // pairings should use type A. If a specific group order must be used (e.g.,
 // for composite orders), then type A1 pairings are required.
 type Params struct {
-	cptr *C.struct_pbc_param_s
+	cptr   *C.struct_pbc_param_s
+	owner  *Params // Holds the finalizer for cptr if it was decoded in place
+	closed bool
 }
 
 // NewParams loads pairing parameters from a Reader.
@@ -97,6 +99,9 @@ func (params *Params) WriteTo(w io.Writer) (n int64, err error) {
 
 // String returns a string representation of the pairing parameters.
 func (params *Params) String() string {
+	if params.cptr == nil {
+		panic(ErrClosed)
+	}
 	var buf *C.char
 	var bufLen C.size_t
 	if C.param_out_str_wrapper(&buf, &bufLen, params.cptr) == 0 {
@@ -107,6 +112,24 @@ func (params *Params) String() string {
 	return str
 }
 
+// Close frees the native memory used by the parameters. Close is idempotent.
+// Pairings that were created from the parameters are not affected, but the
+// parameters themselves cannot be used after being closed.
+func (params *Params) Close() {
+	if params.cptr == nil {
+		return
+	}
+	if params.owner != nil {
+		params.owner.Close()
+		params.owner = nil
+	} else {
+		runtime.SetFinalizer(params, nil)
+		clearParams(params)
+	}
+	params.cptr = nil
+	params.closed = true
+}
+
 func clearParams(params *Params) {
 	C.freeParamStruct(params.cptr)
 }
@@ -0,0 +1,274 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+)
+
+// paramsWriter builds a parameter string in the PBC format and accumulates
+// the first validation failure encountered.
+type paramsWriter struct {
+	buf bytes.Buffer
+	err error
+}
+
+func newParamsWriter(t PairingType) *paramsWriter {
+	w := &paramsWriter{}
+	w.str("type", t.String())
+	return w
+}
+
+func (w *paramsWriter) str(key, value string) {
+	fmt.Fprintf(&w.buf, "%s %s\n", key, value)
+}
+
+func (w *paramsWriter) integer(key string, i int) {
+	w.str(key, fmt.Sprint(i))
+}
+
+func (w *paramsWriter) bigInt(key string, i *big.Int) {
+	if i == nil || i.Sign() < 0 {
+		w.fail()
+		return
+	}
+	w.str(key, i.String())
+}
+
+// fieldElement writes i after checking that it is a canonical element of F_q.
+func (w *paramsWriter) fieldElement(key string, i, q *big.Int) {
+	if i != nil && q != nil && i.Cmp(q) >= 0 {
+		w.fail()
+	}
+	w.bigInt(key, i)
+}
+
+func (w *paramsWriter) check(ok bool) {
+	if !ok {
+		w.fail()
+	}
+}
+
+func (w *paramsWriter) fail() {
+	if w.err == nil {
+		w.err = ErrInvalidParams
+	}
+}
+
+func (w *paramsWriter) params() (*Params, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	return NewParamsFromString(w.buf.String())
+}
+
+func isPrime(i *big.Int) bool {
+	return i != nil && i.ProbablyPrime(20)
+}
+
+// isSolinas returns true if r = 2^exp2 + sign1 * 2^exp1 + sign0.
+func isSolinas(r *big.Int, exp1, exp2, sign0, sign1 int) bool {
+	if r == nil || exp1 < 0 || exp2 <= exp1 || exp2 > r.BitLen() {
+		return false
+	}
+	if (sign0 != 1 && sign0 != -1) || (sign1 != 1 && sign1 != -1) {
+		return false
+	}
+	x := new(big.Int).Lsh(big.NewInt(1), uint(exp2))
+	x.Add(x, new(big.Int).Lsh(big.NewInt(int64(sign1)), uint(exp1)))
+	x.Add(x, big.NewInt(int64(sign0)))
+	return x.Cmp(r) == 0
+}
+
+// withinHasseBound returns true if a curve over F_q can have n points.
+func withinHasseBound(q, n *big.Int) bool {
+	if q == nil || n == nil {
+		return false
+	}
+	t := new(big.Int).Add(q, big.NewInt(1))
+	t.Sub(t, n)
+	t.Mul(t, t)
+	bound := new(big.Int).Lsh(q, 2)
+	return t.Cmp(bound) <= 0
+}
+
+func productEquals(x, y, z *big.Int) bool {
+	if x == nil || y == nil || z == nil {
+		return false
+	}
+	return new(big.Int).Mul(x, y).Cmp(z) == 0
+}
+
+// NewTypeAParams creates type A pairing parameters from their constituent
+// values. The values are checked for consistency: Q and R must be prime,
+// Q = 3 mod 4, H * R = Q + 1, and R must have the Solinas form described by
+// the exponents and signs. If any check fails, NewTypeAParams returns nil and
+// ErrInvalidParams.
+func NewTypeAParams(p TypeAParams) (*Params, error) {
+	w := newParamsWriter(TypeA)
+	w.bigInt("q", p.Q)
+	w.bigInt("h", p.H)
+	w.bigInt("r", p.R)
+	w.integer("exp2", p.Exp2)
+	w.integer("exp1", p.Exp1)
+	w.integer("sign1", p.Sign1)
+	w.integer("sign0", p.Sign0)
+	if w.err == nil {
+		w.check(isPrime(p.Q) && p.Q.Bit(0) == 1 && p.Q.Bit(1) == 1)
+		w.check(isPrime(p.R))
+		w.check(productEquals(p.H, p.R, new(big.Int).Add(p.Q, big.NewInt(1))))
+		w.check(isSolinas(p.R, p.Exp1, p.Exp2, p.Sign0, p.Sign1))
+	}
+	return w.params()
+}
+
+// NewTypeA1Params creates type A1 pairing parameters from their constituent
+// values. The values are checked for consistency: P must be prime,
+// P = 3 mod 4, and L * N = P + 1. If any check fails, NewTypeA1Params returns
+// nil and ErrInvalidParams.
+func NewTypeA1Params(p TypeA1Params) (*Params, error) {
+	w := newParamsWriter(TypeA1)
+	w.bigInt("p", p.P)
+	w.bigInt("n", p.N)
+	w.bigInt("l", p.L)
+	if w.err == nil {
+		w.check(isPrime(p.P) && p.P.Bit(0) == 1 && p.P.Bit(1) == 1)
+		w.check(productEquals(p.L, p.N, new(big.Int).Add(p.P, big.NewInt(1))))
+	}
+	return w.params()
+}
+
+// NewTypeDParams creates type D pairing parameters from their constituent
+// values. The values are checked for consistency: Q and R must be prime,
+// H * R = N, N must satisfy the Hasse bound, K must be 6, HK * R^2 = NK, and
+// all field elements must be reduced modulo Q. If any check fails,
+// NewTypeDParams returns nil and ErrInvalidParams.
+func NewTypeDParams(p TypeDParams) (*Params, error) {
+	w := newParamsWriter(TypeD)
+	w.bigInt("q", p.Q)
+	w.bigInt("n", p.N)
+	w.bigInt("h", p.H)
+	w.bigInt("r", p.R)
+	w.fieldElement("a", p.A, p.Q)
+	w.fieldElement("b", p.B, p.Q)
+	w.integer("k", p.K)
+	w.bigInt("nk", p.NK)
+	w.bigInt("hk", p.HK)
+	w.fieldElement("coeff0", p.Coeff0, p.Q)
+	w.fieldElement("coeff1", p.Coeff1, p.Q)
+	w.fieldElement("coeff2", p.Coeff2, p.Q)
+	w.fieldElement("nqr", p.NQR, p.Q)
+	if w.err == nil {
+		w.check(p.K == 6)
+		w.checkMNT(p.Q, p.N, p.H, p.R, p.NK, p.HK)
+	}
+	return w.params()
+}
+
+// NewTypeEParams creates type E pairing parameters from their constituent
+// values. The values are checked for consistency: Q and R must be prime, R
+// must have the Solinas form described by the exponents and signs, the curve
+// order H * R^2 must equal Q - 1 and satisfy the Hasse bound, and A and B must
+// be reduced modulo Q. If any check fails, NewTypeEParams returns nil and
+// ErrInvalidParams.
+func NewTypeEParams(p TypeEParams) (*Params, error) {
+	w := newParamsWriter(TypeE)
+	w.bigInt("q", p.Q)
+	w.bigInt("r", p.R)
+	w.bigInt("h", p.H)
+	w.fieldElement("a", p.A, p.Q)
+	w.fieldElement("b", p.B, p.Q)
+	w.integer("exp2", p.Exp2)
+	w.integer("exp1", p.Exp1)
+	w.integer("sign1", p.Sign1)
+	w.integer("sign0", p.Sign0)
+	if w.err == nil {
+		w.check(isPrime(p.Q))
+		w.check(isPrime(p.R))
+		w.check(isSolinas(p.R, p.Exp1, p.Exp2, p.Sign0, p.Sign1))
+		n := new(big.Int).Mul(p.R, p.R)
+		n.Mul(n, p.H)
+		w.check(withinHasseBound(p.Q, n))
+		w.check(n.Cmp(new(big.Int).Sub(p.Q, big.NewInt(1))) == 0)
+	}
+	return w.params()
+}
+
+// NewTypeFParams creates type F pairing parameters from their constituent
+// values. The values are checked for consistency: Q and R must be prime, R
+// must satisfy the Hasse bound, Beta must be a quadratic nonresidue modulo Q,
+// and all field elements must be reduced modulo Q. If any check fails,
+// NewTypeFParams returns nil and ErrInvalidParams.
+func NewTypeFParams(p TypeFParams) (*Params, error) {
+	w := newParamsWriter(TypeF)
+	w.bigInt("q", p.Q)
+	w.bigInt("r", p.R)
+	w.fieldElement("b", p.B, p.Q)
+	w.fieldElement("beta", p.Beta, p.Q)
+	w.fieldElement("alpha0", p.Alpha0, p.Q)
+	w.fieldElement("alpha1", p.Alpha1, p.Q)
+	if w.err == nil {
+		w.check(isPrime(p.Q))
+		w.check(isPrime(p.R))
+		w.check(withinHasseBound(p.Q, p.R))
+		w.check(p.Q.Bit(0) == 1 && big.Jacobi(p.Beta, p.Q) == -1)
+	}
+	return w.params()
+}
+
+// NewTypeGParams creates type G pairing parameters from their constituent
+// values. The checks are the same as for NewTypeDParams, except that K must
+// be 10. If any check fails, NewTypeGParams returns nil and ErrInvalidParams.
+func NewTypeGParams(p TypeGParams) (*Params, error) {
+	w := newParamsWriter(TypeG)
+	w.bigInt("q", p.Q)
+	w.bigInt("n", p.N)
+	w.bigInt("h", p.H)
+	w.bigInt("r", p.R)
+	w.fieldElement("a", p.A, p.Q)
+	w.fieldElement("b", p.B, p.Q)
+	w.integer("k", p.K)
+	w.bigInt("nk", p.NK)
+	w.bigInt("hk", p.HK)
+	w.fieldElement("coeff0", p.Coeff0, p.Q)
+	w.fieldElement("coeff1", p.Coeff1, p.Q)
+	w.fieldElement("coeff2", p.Coeff2, p.Q)
+	w.fieldElement("coeff3", p.Coeff3, p.Q)
+	w.fieldElement("coeff4", p.Coeff4, p.Q)
+	w.fieldElement("nqr", p.NQR, p.Q)
+	if w.err == nil {
+		w.check(p.K == 10)
+		w.checkMNT(p.Q, p.N, p.H, p.R, p.NK, p.HK)
+	}
+	return w.params()
+}
+
+// checkMNT validates the values shared by the type D and type G formats.
+func (w *paramsWriter) checkMNT(q, n, h, r, nk, hk *big.Int) {
+	w.check(isPrime(q))
+	w.check(isPrime(r))
+	w.check(productEquals(h, r, n))
+	w.check(withinHasseBound(q, n))
+	w.check(productEquals(hk, new(big.Int).Mul(r, r), nk))
+}
@@ -0,0 +1,199 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+// NamedParamSet describes one of the standard parameter sets that can be
+// loaded with NamedParams.
+type NamedParamSet struct {
+	Name        string
+	Type        PairingType
+	Description string
+
+	// Security holds the estimated security of the parameters, as computed by
+	// Params.SecurityEstimate.
+	Security SecurityLevel
+}
+
+// namedParams holds the standard parameter sets, in the format produced by
+// Params.String. The values have been checked against their defining
+// relations: the primality of q and r, the number of points on the curve (and
+// on the twist for type F), and the irreducibility of the extension field
+// polynomials.
+//
+// The a, d159, and f sets are the ones distributed with the PBC library, under
+// the same names. The other sets differ from PBC's files, so their names have
+// a "go-" prefix to avoid confusion with them. The go-d201, go-d224, and
+// go-g149 sets use the curves from the PBC distribution, but the extension
+// field polynomials and nonresidues were replaced by the smallest valid values
+// (x^3 + x + c or x^5 + x + c with the smallest c), so elements of GT are
+// represented differently than with PBC's files. The go-e set uses the same r
+// as the a set and the smallest h' >= 2^350 for which q = 3 * h'^2 * r^2 + 1
+// is prime, with the curve y^2 = x^3 + 1. The factors of n in the go-a1 set
+// were generated randomly and discarded.
+var namedParams = []struct {
+	name        string
+	typ         PairingType
+	description string
+	params      string
+}{
+	{
+		"a", TypeA, "symmetric pairing with a 160-bit group order over a 512-bit field (PBC's a.param)",
+		"type a\n" +
+			"q 8780710799663312522437781984754049815806883199414208211028653399266475630880222957078625179422662221423155858769582317459277713367317481324925129998224791\n" +
+			"h 12016012264891146079388821366740534204802954401251311822919615131047207289359704531102844802183906537786776\n" +
+			"r 730750818665451621361119245571504901405976559617\n" +
+			"exp2 159\n" +
+			"exp1 107\n" +
+			"sign1 1\n" +
+			"sign0 1\n",
+	},
+	{
+		"go-a1", TypeA1, "composite-order symmetric pairing whose 1023-bit order is the product of two 512-bit primes (not PBC's a1.param)",
+		"type a1\n" +
+			"p 45688258877012658288884362587608742220060695601911307908302566506503668937529255351180434621228555257974696358818597516164261869779197381719382941462326396808526819457075504334423385175688757859391273554198508307588154881931016473560271762002227945788594058068872101742599129585419248219698044034364861055286163\n" +
+			"n 77701120539137173960687691475525071802824312248148482837249262766162702274709617944184412621136998738052204691868363122728336513229927519930923369833888429946474182750128408732012559822599928332298084275847803244197542316209211689728353336738482901001010302838217860106461104737107564999486469446198743291303\n" +
+			"l 588\n",
+	},
+	{
+		"d159", TypeD, "MNT curve with embedding degree 6 and a 159-bit base field (PBC's d159.param)",
+		"type d\n" +
+			"q 625852803282871856053922297323874661378036491717\n" +
+			"n 625852803282871856053923088432465995634661283063\n" +
+			"h 3\n" +
+			"r 208617601094290618684641029477488665211553761021\n" +
+			"a 581595782028432961150765424293919699975513269268\n" +
+			"b 517921465817243828776542439081147840953753552322\n" +
+			"k 6\n" +
+			"nk 60094290356408407130984161127310078516360031868417968262992864809623507269833854678414046779817844853757026858774966331434198257512457993293271849043664655146443229029069463392046837830267994222789160047337432075266619082657640364986415435746294498140589844832666082434658532589211525696\n" +
+			"hk 1380801711862212484403205699005242141541629761433899149236405232528956996854655261075303661691995273080620762287276051361446528504633283152278831183711301329765591450680250000592437612973269056\n" +
+			"coeff0 472731500571015189154958232321864199355792223347\n" +
+			"coeff1 352243926696145937581894994871017455453604730246\n" +
+			"coeff2 289113341693870057212775990719504267185772707305\n" +
+			"nqr 431211441436589568382088865288592347194866189652\n",
+	},
+	{
+		"go-d201", TypeD, "MNT curve with embedding degree 6 and a 201-bit base field (the curve of PBC's d201.param, with a different representation of GT)",
+		"type d\n" +
+			"q 2094476214847295281570670320144695883131009753607350517892357\n" +
+			"n 2094476214847295281570670320143248652598286201895740019876423\n" +
+			"h 1122591\n" +
+			"r 1865751832009427548920907365321162072917283500309320153\n" +
+			"a 9937051644888803031325524114144300859517912378923477935510\n" +
+			"b 6624701096592535354217016076096200573011941585948985290340\n" +
+			"k 6\n" +
+			"nk 84421409121513221644716967251498543569964760150943970280296295496165154657097987617093928595467244393873913569302597521196137376192587250931727762632568620562823714441576400096248911214941742242106512149305076320555351603145285797909942596124862593877499051211952936404822228308154770272833273836975042632765377879565229109013234552083886934379264203243445590336\n" +
+			"hk 24251848326363771171270027814768648115136299306034875585195931346818912374815385257266068811350396365799298585287746735681314613260560203359251331805443378322987677594618057568388400134442772232086258797844238238645130212769322779762522643806720212266304\n" +
+			"coeff0 3\n" +
+			"coeff1 1\n" +
+			"coeff2 0\n" +
+			"nqr 2\n",
+	},
+	{
+		"go-d224", TypeD, "MNT curve with embedding degree 6 and a 224-bit base field (the curve of PBC's d224.param, with a different representation of GT)",
+		"type d\n" +
+			"q 15028799613985034465755506450771565229282832217860390155996483840017\n" +
+			"n 15028799613985034465755506450771561352583254744125520639296541195021\n" +
+			"h 1\n" +
+			"r 15028799613985034465755506450771561352583254744125520639296541195021\n" +
+			"a 1871224163624666631860092489128939059944978347142292177323825642096\n" +
+			"b 9795501723343380547144152006776653149306466138012730640114125605701\n" +
+			"k 6\n" +
+			"nk 11522474695025217370062603013790980334538096429455689114222024912184432319228393204650383661781864806076247259556378350541669994344878430136202714945761488385890619925553457668158504202786580559970945936657636855346713598888067516214634859330554634505767198415857150479345944721710356274047707536156296215573412763735135600953865419000398920292535215757291539307525639675204597938919504807427238735811520\n" +
+			"hk 51014915936684265604900487195256160848193571244274648855332475661658304506316301006112887177277345010864012988127829655449256424871024500368597989462373813062189274150916552689262852603254011248502356041206544262755481779137398040376281542938513970473990787064615734720\n" +
+			"coeff0 3\n" +
+			"coeff1 1\n" +
+			"coeff2 0\n" +
+			"nqr 5\n",
+	},
+	{
+		"go-e", TypeE, "curve with embedding degree 1, a 160-bit group order, and a 1020-bit base field (not PBC's e.param)",
+		"type e\n" +
+			"q 8426686569667109559681698459423615762257814116378455891142967880967013806691578975310173165937123706479689293427376797233359156928586862484852284413974384407895509592979167674239121657927698848470768417613373943314462347028758212726757827734996825785845272892246765529310448092523732977121851813072428718849\n" +
+			"r 730750818665451621361119245571504901405976559617\n" +
+			"h 15780407704645120521722969648640385996651019408469521578494842709206196462891242466340999608832765353687334006715451012227382822123072421632561081241800137198351118342238527081605332979319899803672469666576063232\n" +
+			"a 0\n" +
+			"b 1\n" +
+			"exp2 159\n" +
+			"exp1 107\n" +
+			"sign1 1\n" +
+			"sign0 1\n",
+	},
+	{
+		"f", TypeF, "Barreto-Naehrig curve with embedding degree 12 and a 160-bit base field (PBC's f.param)",
+		"type f\n" +
+			"q 205523667896953300194896352429254920972540065223\n" +
+			"r 205523667896953300194895899082072403858390252929\n" +
+			"b 40218105156867728698573668525883168222119515413\n" +
+			"beta 115334401956802802075595682801335644058796914268\n" +
+			"alpha0 191079354656274778837764015557338301375963168470\n" +
+			"alpha1 71445317903696340296199556072836940741717506375\n",
+	},
+	{
+		"go-g149", TypeG, "Freeman curve with embedding degree 10 and a 149-bit base field (the curve of PBC's g149.param, with a different representation of GT)",
+		"type g\n" +
+			"q 503189899097385532598615948567975432740967203\n" +
+			"n 503189899097385532598571084778608176410973351\n" +
+			"h 1\n" +
+			"r 503189899097385532598571084778608176410973351\n" +
+			"a 465197998498440909244782433627180757481058321\n" +
+			"b 463074517126110479409374670871346701448503064\n" +
+			"k 10\n" +
+			"nk 1040684643531490707494989587381629956832530311976146077888095795458709511789670022388326295177424065807612879371896982185473788988016190582073591316127396374860265835641044035656044524481121528846249501655527462202999638159773731830375673076317719519977183373353791119388388468745670818193868532404392452816602538968163226713846951514831917487400267590451867746120591750902040267826351982737642689423713163967384383105678367875981348397359466338807\n" +
+			"hk 4110127713690841149713310614420858884651261781185442551927080083178682965171097172366598236129731931693425629387502221804555636704708008882811353539555915064049685663790355716130262332064327767695339422323460458479884756000782939428852120522712008037615051139080628734566850259704397643028017435446110322024094259858170303605703280329322675124728639532674407\n" +
+			"coeff0 10\n" +
+			"coeff1 1\n" +
+			"coeff2 0\n" +
+			"coeff3 0\n" +
+			"coeff4 0\n" +
+			"nqr 2\n",
+	},
+}
+
+// NamedParams returns a new copy of the standard parameter set with the given
+// name, or ErrUnknownParams if there is no such set. Applications can use the
+// names to agree on parameters without exchanging them. See NamedParamSets for
+// the available sets.
+//
+// For example:
+// 	params, err := pbc.NamedParams("a")
+func NamedParams(name string) (*Params, error) {
+	for _, set := range namedParams {
+		if set.name == name {
+			return NewParamsFromString(set.params)
+		}
+	}
+	return nil, ErrUnknownParams
+}
+
+// NamedParamSets returns descriptions of the parameter sets available through
+// NamedParams.
+func NamedParamSets() []NamedParamSet {
+	sets := make([]NamedParamSet, len(namedParams))
+	for i, set := range namedParams {
+		sets[i] = NamedParamSet{Name: set.name, Type: set.typ, Description: set.description}
+		if params, err := NewParamsFromString(set.params); err == nil {
+			sets[i].Security = params.SecurityEstimate()
+			params.Close()
+		}
+	}
+	return sets
+}
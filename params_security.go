@@ -0,0 +1,146 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"math"
+	"math/big"
+)
+
+// SecurityLevel holds estimates of the security of a set of parameters, in
+// bits. An estimate of b bits means that the best known attack is expected to
+// take roughly 2^b operations.
+type SecurityLevel struct {
+	// Groups is the estimated cost of computing discrete logarithms in G1 and
+	// G2 (the elliptic curve discrete logarithm problem).
+	Groups int
+
+	// GT is the estimated cost of computing discrete logarithms in GT, which
+	// is a subgroup of the multiplicative group of F_q^k.
+	GT int
+}
+
+// Bits returns the overall security level, which is the smaller of the two
+// estimates.
+func (level SecurityLevel) Bits() int {
+	if level.GT < level.Groups {
+		return level.GT
+	}
+	return level.Groups
+}
+
+// Constants for the L_N[1/3, c] complexity of the number field sieve variants
+// that apply to each kind of target field. For composite k, the best attack is
+// the extended tower NFS (exTNFS), and for Barreto-Naehrig curves, whose
+// characteristic is given by a polynomial, the special extended tower NFS
+// (SexTNFS). Otherwise, the constant of the general NFS in prime fields is
+// used. This is a simplification for k = 2: the conjugation variant of the NFS
+// can be faster in F_q^2 for some sizes of q, so the estimate may be a few
+// bits too high there.
+const (
+	nfsConstant     = 1.923 // (64/9)^(1/3)
+	exTNFSConstant  = 1.747 // (48/9)^(1/3)
+	sexTNFSConstant = 1.526 // (32/9)^(1/3)
+
+	// nfsCalibration is subtracted from the asymptotic estimates so that they
+	// agree with record computations (e.g., giving 80 bits for 1024-bit
+	// fields).
+	nfsCalibration = 7
+)
+
+// SecurityEstimate estimates the security provided by the parameters. The
+// security in G1 and G2 is based on Pollard's rho algorithm in the subgroup
+// of order r. The security in GT is based on the L_N[1/3] complexity of the
+// number field sieve variant that applies to F_q^k, taking into account the
+// tower number field sieve for composite embedding degrees and for
+// Barreto-Naehrig curves. For type A1 pairings, both estimates are also
+// limited by the cost of factoring the composite group order n, assuming that
+// n has two prime factors of equal size.
+//
+// The estimates are based on asymptotic formulas and are only accurate to
+// within a few bits. They are intended to detect weak parameters, not to
+// compare parameters of similar strength.
+func (params *Params) SecurityEstimate() SecurityLevel {
+	t := params.Type()
+	r := params.Order()
+	k := params.EmbeddingDegree()
+	fieldBits := k * params.FieldCharacteristic().BitLen()
+
+	c := nfsConstant
+	switch {
+	case t == TypeF:
+		c = sexTNFSConstant
+	case k > 2 && !isPrime(big.NewInt(int64(k))):
+		c = exTNFSConstant
+	}
+	level := SecurityLevel{Groups: rhoSecurity(r), GT: nfsSecurity(fieldBits, c)}
+	if level.GT > level.Groups {
+		// Discrete logarithms in GT can also be computed in the subgroup
+		level.GT = level.Groups
+	}
+
+	if t == TypeA1 {
+		factoring := nfsSecurity(r.BitLen(), nfsConstant)
+		if rho := rhoSecurity(new(big.Int).Rsh(r, uint(r.BitLen()/2))); rho < factoring {
+			factoring = rho
+		}
+		if factoring < level.Groups {
+			level.Groups = factoring
+		}
+		if factoring < level.GT {
+			level.GT = factoring
+		}
+	}
+	return level
+}
+
+// rhoSecurity estimates the cost of Pollard's rho algorithm in a group of
+// order r, which is about sqrt(pi * r / 4) operations.
+func rhoSecurity(r *big.Int) int {
+	if r.Sign() <= 0 {
+		return 0
+	}
+	mant := new(big.Float)
+	exp := new(big.Float).SetInt(r).MantExp(mant)
+	f, _ := mant.Float64()
+	bits := float64(exp) + math.Log2(f)
+	level := int(bits/2 + math.Log2(math.Pi/4)/2)
+	if level < 0 {
+		return 0
+	}
+	return level
+}
+
+// nfsSecurity estimates the cost of computing discrete logarithms (or
+// factoring) with a number field sieve variant of complexity L_N[1/3, c],
+// where N has the given number of bits.
+func nfsSecurity(bits int, c float64) int {
+	lnN := float64(bits) * math.Ln2
+	if lnN <= 1 {
+		return 0
+	}
+	level := int(c*math.Cbrt(lnN)*math.Pow(math.Log(lnN), 2.0/3)/math.Ln2) - nfsCalibration
+	if level < 0 {
+		return 0
+	}
+	return level
+}
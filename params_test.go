@@ -0,0 +1,298 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParamsIntrospection(t *testing.T) {
+	params, err := NewParamsFromString(testParamsString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Type() != TypeA {
+		t.Fatalf("wrong type: %s", params.Type())
+	}
+	if params.Order().Cmp(big.NewInt(641)) != 0 {
+		t.Fatalf("wrong order: %s", params.Order())
+	}
+	if params.FieldCharacteristic().Cmp(big.NewInt(4025338979)) != 0 {
+		t.Fatalf("wrong field characteristic: %s", params.FieldCharacteristic())
+	}
+	if params.Cofactor().Cmp(big.NewInt(6279780)) != 0 {
+		t.Fatalf("wrong cofactor: %s", params.Cofactor())
+	}
+	if params.EmbeddingDegree() != 2 {
+		t.Fatalf("wrong embedding degree: %d", params.EmbeddingDegree())
+	}
+
+	a, err := params.TypeA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Exp1 != 7 || a.Exp2 != 9 || a.Sign0 != 1 || a.Sign1 != 1 {
+		t.Fatalf("wrong exponents: %+v", a)
+	}
+	if _, err := params.TypeD(); err != ErrWrongParamsType {
+		t.Fatalf("expected ErrWrongParamsType, got %v", err)
+	}
+}
+
+func TestNewTypeAParams(t *testing.T) {
+	params, err := NewParamsFromString(testParamsString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := params.TypeA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuilt, err := NewTypeAParams(*a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebuilt.String() != params.String() {
+		t.Fatalf("rebuilt parameters differ:\n%s\n%s", rebuilt, params)
+	}
+
+	a.H = new(big.Int).Add(a.H, big.NewInt(1))
+	if _, err := NewTypeAParams(*a); err != ErrInvalidParams {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+
+	a.H.Sub(a.H, big.NewInt(1))
+	a.Exp2 = 1 << 40
+	if _, err := NewTypeAParams(*a); err != ErrInvalidParams {
+		t.Fatalf("expected ErrInvalidParams for a huge exponent, got %v", err)
+	}
+}
+
+func TestNewTypeParams(t *testing.T) {
+	for _, set := range NamedParamSets() {
+		params, err := NamedParams(set.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rebuilt := rebuildParams(t, params); rebuilt.String() != params.String() {
+			t.Errorf("%s: rebuilt parameters differ", set.Name)
+		}
+
+		one := big.NewInt(1)
+		switch set.Type {
+		case TypeA1:
+			p, _ := params.TypeA1()
+			p.L = new(big.Int).Add(p.L, one)
+			_, err = NewTypeA1Params(*p)
+		case TypeD:
+			p, _ := params.TypeD()
+			p.NK = new(big.Int).Add(p.NK, one)
+			_, err = NewTypeDParams(*p)
+		case TypeE:
+			p, _ := params.TypeE()
+			p.H = new(big.Int).Add(p.H, one)
+			_, err = NewTypeEParams(*p)
+		case TypeF:
+			p, _ := params.TypeF()
+			p.Beta = big.NewInt(4)
+			_, err = NewTypeFParams(*p)
+		case TypeG:
+			p, _ := params.TypeG()
+			p.K = 6
+			_, err = NewTypeGParams(*p)
+		default:
+			continue
+		}
+		if err != ErrInvalidParams {
+			t.Errorf("%s: expected ErrInvalidParams, got %v", set.Name, err)
+		}
+	}
+}
+
+func TestPairingParams(t *testing.T) {
+	params, err := NewParamsFromString(testParamsString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairing := params.NewPairing()
+	if pairing.Params() != params {
+		t.Fatal("pairing did not retain its parameters")
+	}
+	if pairing.ParamsString() != params.String() {
+		t.Fatalf("unexpected parameter string: %s", pairing.ParamsString())
+	}
+}
+
+func TestPairingOrder(t *testing.T) {
+	pairing := testPairing(t)
+	r := big.NewInt(641)
+	if pairing.Order().Cmp(r) != 0 {
+		t.Fatalf("wrong order: %s", pairing.Order())
+	}
+	if pairing.G1Cofactor().Cmp(big.NewInt(6279780)) != 0 {
+		t.Fatalf("wrong cofactor: %s", pairing.G1Cofactor())
+	}
+	for _, field := range []Field{G1, G2, GT, Zr} {
+		if pairing.FieldOrder(field).Cmp(r) != 0 {
+			t.Fatalf("wrong order for field %d: %s", field, pairing.FieldOrder(field))
+		}
+	}
+	if pairing.NewGT().Field() != GT || pairing.NewZr().NewFieldElement().Field() != Zr {
+		t.Fatal("elements report the wrong field")
+	}
+}
+
+func TestNamedParams(t *testing.T) {
+	sets := NamedParamSets()
+	names := []string{"a", "go-a1", "d159", "go-d201", "go-d224", "go-e", "f", "go-g149"}
+	if len(sets) != len(names) {
+		t.Fatalf("found %d named parameter sets", len(sets))
+	}
+	for i, set := range sets {
+		if set.Name != names[i] {
+			t.Errorf("set %d is named %s", i, set.Name)
+		}
+		params, err := NamedParams(set.Name)
+		if err != nil {
+			t.Fatalf("%s: %v", set.Name, err)
+		}
+		if params.Type() != set.Type {
+			t.Errorf("%s: wrong type %s", set.Name, params.Type())
+		}
+		if set.Security != params.SecurityEstimate() || set.Security.Bits() < 64 {
+			t.Errorf("%s: unexpected security %+v", set.Name, set.Security)
+		}
+		if rebuilt := rebuildParams(t, params); rebuilt.String() != params.String() {
+			t.Errorf("%s: rebuilt parameters differ:\n%s\n%s", set.Name, rebuilt, params)
+		}
+		pairing := params.NewPairing()
+		g, h := pairing.NewG1().Rand(), pairing.NewG2().Rand()
+		x := pairing.NewZr().Rand()
+		lhs := pairing.NewGT().Pair(pairing.NewG1().PowZn(g, x), h)
+		rhs := pairing.NewGT().PowZn(pairing.NewGT().Pair(g, h), x)
+		if !lhs.Equals(rhs) {
+			t.Errorf("%s: pairing is not bilinear", set.Name)
+		}
+	}
+	if _, err := NamedParams("unknown"); err != ErrUnknownParams {
+		t.Errorf("unknown name returned %v", err)
+	}
+}
+
+// rebuildParams reconstructs params from their values, which checks that the
+// values are consistent.
+func rebuildParams(t *testing.T, params *Params) *Params {
+	var rebuilt *Params
+	var err error
+	switch params.Type() {
+	case TypeA:
+		var p *TypeAParams
+		if p, err = params.TypeA(); err == nil {
+			rebuilt, err = NewTypeAParams(*p)
+		}
+	case TypeA1:
+		var p *TypeA1Params
+		if p, err = params.TypeA1(); err == nil {
+			rebuilt, err = NewTypeA1Params(*p)
+		}
+	case TypeD:
+		var p *TypeDParams
+		if p, err = params.TypeD(); err == nil {
+			rebuilt, err = NewTypeDParams(*p)
+		}
+	case TypeE:
+		var p *TypeEParams
+		if p, err = params.TypeE(); err == nil {
+			rebuilt, err = NewTypeEParams(*p)
+		}
+	case TypeF:
+		var p *TypeFParams
+		if p, err = params.TypeF(); err == nil {
+			rebuilt, err = NewTypeFParams(*p)
+		}
+	case TypeG:
+		var p *TypeGParams
+		if p, err = params.TypeG(); err == nil {
+			rebuilt, err = NewTypeGParams(*p)
+		}
+	}
+	if err != nil {
+		t.Fatalf("%s: %v", params.Type(), err)
+	}
+	return rebuilt
+}
+
+func TestSecurityEstimate(t *testing.T) {
+	expected := map[string]SecurityLevel{
+		"a":       {Groups: 79, GT: 79},
+		"go-a1":   {Groups: 79, GT: 79},
+		"d159":    {Groups: 78, GT: 69},
+		"go-d201": {Groups: 89, GT: 77},
+		"go-d224": {Groups: 111, GT: 81},
+		"go-e":    {Groups: 79, GT: 79},
+		"f":       {Groups: 78, GT: 78},
+		"go-g149": {Groups: 74, GT: 74},
+	}
+	for name, want := range expected {
+		params, err := NamedParams(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := params.SecurityEstimate(); got != want {
+			t.Errorf("%s: estimated %+v, expected %+v", name, got, want)
+		}
+	}
+
+	// Compare the formulas with the comparable strengths given in Table 2 of
+	// NIST SP 800-57 Part 1, Revision 5, for finite field and elliptic curve
+	// cryptography
+	for _, c := range []struct{ ffBits, ecBits, security int }{
+		{1024, 160, 80}, {2048, 224, 112}, {3072, 256, 128}, {7680, 384, 192}, {15360, 512, 256},
+	} {
+		if got := nfsSecurity(c.ffBits, nfsConstant); got < c.security-8 || got > c.security+8 {
+			t.Errorf("%d-bit field estimated at %d bits, expected %d", c.ffBits, got, c.security)
+		}
+		r := new(big.Int).Lsh(big.NewInt(1), uint(c.ecBits-1))
+		if got := rhoSecurity(r); got < c.security-1 || got > c.security {
+			t.Errorf("%d-bit group estimated at %d bits, expected %d", c.ecBits, got, c.security)
+		}
+	}
+
+	params, err := NewParamsFromString(testParamsString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level := params.SecurityEstimate(); level.Bits() != 4 {
+		t.Errorf("toy parameters estimated at %+v", level)
+	}
+	if _, err := NewPairingWithOptions(params, MinimumSecurity(64)); err != ErrInsecureParams {
+		t.Errorf("weak parameters returned %v", err)
+	}
+	if pairing, err := NewPairingWithOptions(params); err != nil || pairing == nil {
+		t.Errorf("unguarded pairing returned %v", err)
+	}
+	params, _ = NamedParams("a")
+	if _, err := NewPairingWithOptions(params, MinimumSecurity(72)); err != nil {
+		t.Errorf("strong parameters returned %v", err)
+	}
+}
@@ -0,0 +1,397 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// PairingType denotes the family of curves used to construct a pairing. Each
+// type corresponds to one of the generation functions (GenerateA,
+// GenerateA1, and so on) and to the "type" line of the PBC parameter format.
+type PairingType int
+
+const (
+	TypeA  PairingType = iota
+	TypeA1 PairingType = iota
+	TypeD  PairingType = iota
+	TypeE  PairingType = iota
+	TypeF  PairingType = iota
+	TypeG  PairingType = iota
+)
+
+var pairingTypeNames = [...]string{"a", "a1", "d", "e", "f", "g"}
+
+// String returns the name used for the pairing type in the PBC parameter
+// format (e.g., "a" or "a1").
+func (t PairingType) String() string {
+	if t < 0 || int(t) >= len(pairingTypeNames) {
+		return "PairingType(" + strconv.Itoa(int(t)) + ")"
+	}
+	return pairingTypeNames[t]
+}
+
+// TypeAParams holds the values describing a type A pairing. The curve is
+// y^2 = x^3 + x over F_q, where q + 1 = h * r and the group order r is the
+// Solinas prime 2^Exp2 + Sign1 * 2^Exp1 + Sign0.
+type TypeAParams struct {
+	Q, R, H      *big.Int
+	Exp1, Exp2   int
+	Sign0, Sign1 int
+}
+
+// TypeA1Params holds the values describing a type A1 pairing. The curve is
+// y^2 = x^3 + x over F_P, where P = L * N - 1 and N is the (typically
+// composite) group order.
+type TypeA1Params struct {
+	P, N, L *big.Int
+}
+
+// TypeDParams holds the values describing a type D (MNT) pairing. The curve is
+// y^2 = x^3 + A*x + B over F_Q with N = H * R points. The extension field
+// F_Q^3 is defined by the irreducible polynomial
+// x^3 + Coeff2*x^2 + Coeff1*x + Coeff0, and NQR is a quadratic nonresidue in
+// F_Q^3. NK is the number of points of the curve over F_Q^K, and HK = NK / R^2.
+type TypeDParams struct {
+	Q, N, H, R             *big.Int
+	A, B                   *big.Int
+	K                      int
+	NK, HK                 *big.Int
+	Coeff0, Coeff1, Coeff2 *big.Int
+	NQR                    *big.Int
+}
+
+// TypeEParams holds the values describing a type E pairing. The curve is
+// y^2 = x^3 + A*x + B over F_Q, with H * R^2 = Q - 1 points, where the group
+// order R is the Solinas prime 2^Exp2 + Sign1 * 2^Exp1 + Sign0.
+type TypeEParams struct {
+	Q, R, H      *big.Int
+	A, B         *big.Int
+	Exp1, Exp2   int
+	Sign0, Sign1 int
+}
+
+// TypeFParams holds the values describing a type F (Barreto-Naehrig) pairing.
+// The curve is y^2 = x^3 + B over F_Q with R points. Beta is a quadratic
+// nonresidue in F_Q used to construct F_Q^2, and Alpha0 + Alpha1*sqrt(Beta)
+// defines the sextic twist used for G2.
+type TypeFParams struct {
+	Q, R, B        *big.Int
+	Beta           *big.Int
+	Alpha0, Alpha1 *big.Int
+}
+
+// TypeGParams holds the values describing a type G (Freeman) pairing. The
+// fields have the same meaning as in TypeDParams, except that the extension
+// field F_Q^5 is defined by the polynomial
+// x^5 + Coeff4*x^4 + Coeff3*x^3 + Coeff2*x^2 + Coeff1*x + Coeff0.
+type TypeGParams struct {
+	Q, N, H, R                             *big.Int
+	A, B                                   *big.Int
+	K                                      int
+	NK, HK                                 *big.Int
+	Coeff0, Coeff1, Coeff2, Coeff3, Coeff4 *big.Int
+	NQR                                    *big.Int
+}
+
+// paramValues holds the key-value pairs of the PBC parameter format.
+type paramValues map[string]string
+
+func parseParamValues(s string) (PairingType, paramValues, error) {
+	values := make(paramValues)
+	for _, line := range strings.Split(s, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return 0, nil, ErrInvalidParamString
+		}
+		values[fields[0]] = fields[1]
+	}
+	for t, name := range pairingTypeNames {
+		if values["type"] == name {
+			return PairingType(t), values, nil
+		}
+	}
+	return 0, nil, ErrInvalidParamString
+}
+
+func (values paramValues) bigInt(key string, err *error) *big.Int {
+	i, ok := new(big.Int).SetString(values[key], 10)
+	if !ok && *err == nil {
+		*err = ErrInvalidParamString
+	}
+	return i
+}
+
+func (values paramValues) integer(key string, err *error) int {
+	i, convErr := strconv.Atoi(values[key])
+	if convErr != nil && *err == nil {
+		*err = ErrInvalidParamString
+	}
+	return i
+}
+
+func (values paramValues) typeA() (p *TypeAParams, err error) {
+	p = &TypeAParams{
+		Q:     values.bigInt("q", &err),
+		R:     values.bigInt("r", &err),
+		H:     values.bigInt("h", &err),
+		Exp1:  values.integer("exp1", &err),
+		Exp2:  values.integer("exp2", &err),
+		Sign0: values.integer("sign0", &err),
+		Sign1: values.integer("sign1", &err),
+	}
+	return
+}
+
+func (values paramValues) typeA1() (p *TypeA1Params, err error) {
+	p = &TypeA1Params{
+		P: values.bigInt("p", &err),
+		N: values.bigInt("n", &err),
+		L: values.bigInt("l", &err),
+	}
+	return
+}
+
+func (values paramValues) typeD() (p *TypeDParams, err error) {
+	p = &TypeDParams{
+		Q:      values.bigInt("q", &err),
+		N:      values.bigInt("n", &err),
+		H:      values.bigInt("h", &err),
+		R:      values.bigInt("r", &err),
+		A:      values.bigInt("a", &err),
+		B:      values.bigInt("b", &err),
+		K:      values.integer("k", &err),
+		NK:     values.bigInt("nk", &err),
+		HK:     values.bigInt("hk", &err),
+		Coeff0: values.bigInt("coeff0", &err),
+		Coeff1: values.bigInt("coeff1", &err),
+		Coeff2: values.bigInt("coeff2", &err),
+		NQR:    values.bigInt("nqr", &err),
+	}
+	return
+}
+
+func (values paramValues) typeE() (p *TypeEParams, err error) {
+	p = &TypeEParams{
+		Q:     values.bigInt("q", &err),
+		R:     values.bigInt("r", &err),
+		H:     values.bigInt("h", &err),
+		A:     values.bigInt("a", &err),
+		B:     values.bigInt("b", &err),
+		Exp1:  values.integer("exp1", &err),
+		Exp2:  values.integer("exp2", &err),
+		Sign0: values.integer("sign0", &err),
+		Sign1: values.integer("sign1", &err),
+	}
+	return
+}
+
+func (values paramValues) typeF() (p *TypeFParams, err error) {
+	p = &TypeFParams{
+		Q:      values.bigInt("q", &err),
+		R:      values.bigInt("r", &err),
+		B:      values.bigInt("b", &err),
+		Beta:   values.bigInt("beta", &err),
+		Alpha0: values.bigInt("alpha0", &err),
+		Alpha1: values.bigInt("alpha1", &err),
+	}
+	return
+}
+
+func (values paramValues) typeG() (p *TypeGParams, err error) {
+	p = &TypeGParams{
+		Q:      values.bigInt("q", &err),
+		N:      values.bigInt("n", &err),
+		H:      values.bigInt("h", &err),
+		R:      values.bigInt("r", &err),
+		A:      values.bigInt("a", &err),
+		B:      values.bigInt("b", &err),
+		K:      values.integer("k", &err),
+		NK:     values.bigInt("nk", &err),
+		HK:     values.bigInt("hk", &err),
+		Coeff0: values.bigInt("coeff0", &err),
+		Coeff1: values.bigInt("coeff1", &err),
+		Coeff2: values.bigInt("coeff2", &err),
+		Coeff3: values.bigInt("coeff3", &err),
+		Coeff4: values.bigInt("coeff4", &err),
+		NQR:    values.bigInt("nqr", &err),
+	}
+	return
+}
+
+// values parses the textual form of params. Parameters produced by PBC are
+// always well-formed, so a failure indicates memory corruption.
+func (params *Params) values() (PairingType, paramValues) {
+	t, values, err := parseParamValues(params.String())
+	if err != nil {
+		panic(ErrInternal)
+	}
+	return t, values
+}
+
+// Type returns the type of pairing described by params.
+func (params *Params) Type() PairingType {
+	t, _ := params.values()
+	return t
+}
+
+// TypeA returns the values of a type A pairing. If params describe a
+// different type of pairing, TypeA returns nil and ErrWrongParamsType.
+func (params *Params) TypeA() (*TypeAParams, error) {
+	t, values := params.values()
+	if t != TypeA {
+		return nil, ErrWrongParamsType
+	}
+	return values.typeA()
+}
+
+// TypeA1 returns the values of a type A1 pairing. If params describe a
+// different type of pairing, TypeA1 returns nil and ErrWrongParamsType.
+func (params *Params) TypeA1() (*TypeA1Params, error) {
+	t, values := params.values()
+	if t != TypeA1 {
+		return nil, ErrWrongParamsType
+	}
+	return values.typeA1()
+}
+
+// TypeD returns the values of a type D pairing. If params describe a
+// different type of pairing, TypeD returns nil and ErrWrongParamsType.
+func (params *Params) TypeD() (*TypeDParams, error) {
+	t, values := params.values()
+	if t != TypeD {
+		return nil, ErrWrongParamsType
+	}
+	return values.typeD()
+}
+
+// TypeE returns the values of a type E pairing. If params describe a
+// different type of pairing, TypeE returns nil and ErrWrongParamsType.
+func (params *Params) TypeE() (*TypeEParams, error) {
+	t, values := params.values()
+	if t != TypeE {
+		return nil, ErrWrongParamsType
+	}
+	return values.typeE()
+}
+
+// TypeF returns the values of a type F pairing. If params describe a
+// different type of pairing, TypeF returns nil and ErrWrongParamsType.
+func (params *Params) TypeF() (*TypeFParams, error) {
+	t, values := params.values()
+	if t != TypeF {
+		return nil, ErrWrongParamsType
+	}
+	return values.typeF()
+}
+
+// TypeG returns the values of a type G pairing. If params describe a
+// different type of pairing, TypeG returns nil and ErrWrongParamsType.
+func (params *Params) TypeG() (*TypeGParams, error) {
+	t, values := params.values()
+	if t != TypeG {
+		return nil, ErrWrongParamsType
+	}
+	return values.typeG()
+}
+
+// Order returns r, the order of the groups G1, G2, and GT. For type A1
+// pairings, this is the composite order n.
+func (params *Params) Order() *big.Int {
+	t, values := params.values()
+	var err error
+	var order *big.Int
+	switch t {
+	case TypeA1:
+		order = values.bigInt("n", &err)
+	default:
+		order = values.bigInt("r", &err)
+	}
+	if err != nil {
+		panic(ErrInternal)
+	}
+	return order
+}
+
+// FieldCharacteristic returns q, the characteristic of the field over which
+// the curve is defined. For type A1 pairings, this is the prime p.
+func (params *Params) FieldCharacteristic() *big.Int {
+	t, values := params.values()
+	var err error
+	var q *big.Int
+	switch t {
+	case TypeA1:
+		q = values.bigInt("p", &err)
+	default:
+		q = values.bigInt("q", &err)
+	}
+	if err != nil {
+		panic(ErrInternal)
+	}
+	return q
+}
+
+// Cofactor returns h, the number of points on the curve over the base field
+// divided by the group order. Type F curves have prime order, so their
+// cofactor is always 1.
+func (params *Params) Cofactor() *big.Int {
+	t, values := params.values()
+	var err error
+	var h *big.Int
+	switch t {
+	case TypeA1:
+		h = values.bigInt("l", &err)
+	case TypeF:
+		h = big.NewInt(1)
+	default:
+		h = values.bigInt("h", &err)
+	}
+	if err != nil {
+		panic(ErrInternal)
+	}
+	return h
+}
+
+// EmbeddingDegree returns k, the degree of the extension of the base field
+// that contains GT.
+func (params *Params) EmbeddingDegree() int {
+	t, values := params.values()
+	switch t {
+	case TypeA, TypeA1:
+		return 2
+	case TypeE:
+		return 1
+	case TypeF:
+		return 12
+	}
+	var err error
+	k := values.integer("k", &err)
+	if err != nil {
+		panic(ErrInternal)
+	}
+	return k
+}
@@ -28,15 +28,28 @@ import (
 	"testing"
 )
 
+// Generated with pbc_param_init_a_gen(p, 10, 32);
+const testParamsString = "type a\nq 4025338979\nh 6279780\nr 641\nexp2 9\nexp1 7\nsign1 1\nsign0 1\n"
+
 func testPairing(t *testing.T) *Pairing {
-	// Generated with pbc_param_init_a_gen(p, 10, 32);
-	pairing, err := NewPairingFromString("type a\nq 4025338979\nh 6279780\nr 641\nexp2 9\nexp1 7\nsign1 1\nsign0 1\n")
+	pairing, err := NewPairingFromString(testParamsString)
 	if err != nil {
 		t.Fatalf("Could not instantiate test pairing")
 	}
 	return pairing
 }
 
+// expectPanic fails the test unless f panics with expected.
+func expectPanic(t *testing.T, expected error, f func()) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != expected {
+			t.Errorf("expected panic with %v, got %v", expected, r)
+		}
+	}()
+	f()
+}
+
 func logElement(e *Element, name string, t *testing.T) {
 	t.Logf("%s = %s\n", name, e)
 }
@@ -532,3 +545,41 @@ func TestGC(t *testing.T) {
 		runtime.GC()
 	}
 }
+
+// TestClose ensures that explicitly freed objects can coexist with the
+// garbage collector, regardless of the order in which they are closed.
+func TestClose(t *testing.T) {
+	pairing := testPairing(t)
+	g := pairing.NewG1().Rand()
+	h := pairing.NewG1()
+	x := pairing.NewZr().Rand()
+	power := g.PreparePower()
+	pairer := g.PreparePairer()
+	target := pairing.NewGT()
+
+	// Existing objects remain usable after their pairing is closed
+	pairing.Close()
+	pairing.Close()
+	power.PowZn(h, x)
+	pairer.Pair(target, h)
+
+	expectPanic(t, ErrClosed, func() { pairing.NewG1() })
+
+	power.Close()
+	pairer.Close()
+	x.Close()
+	x.Close()
+	expectPanic(t, ErrClosed, func() { h.MulZn(g, x) })
+
+	// Releasing the last element frees the pairing
+	g.Close()
+	h.Close()
+	target.Close()
+	expectPanic(t, ErrClosed, func() { pairing.Order() })
+	expectPanic(t, ErrClosed, func() { pairing.FieldOrder(GT) })
+	expectPanic(t, ErrClosed, func() { pairing.G1Length() })
+	expectPanic(t, ErrClosed, func() { pairing.IsSymmetric() })
+	for i := 0; i < 5; i++ {
+		runtime.GC()
+	}
+}
@@ -22,17 +22,19 @@
 #include "_cgo_export.h"
 #include <pbc/pbc.h>
 
-void pbc_init_random();
+// randomHandle is the cgo.Handle of the RandomSource used by the calling
+// thread, or 0 if the thread uses the global source.
+static __thread uintptr_t randomHandle;
 
 void goRandomHook(mpz_t out, mpz_t limit, void* data) {
 	UNUSED_VAR(data);
-	goGenerateRandom(&out, &limit);
+	goGenerateRandom(&out, &limit, randomHandle);
 }
 
 void installRandomHook() {
 	pbc_random_set_function(goRandomHook, NULL);
 }
 
-void uninstallRandomHook() {
-	pbc_init_random();
+void setRandomHandle(uintptr_t handle) {
+	randomHandle = handle;
 }
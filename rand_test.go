@@ -0,0 +1,48 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("no entropy") }
+
+func TestRandFrom(t *testing.T) {
+	pairing := testPairing(t)
+	for _, field := range []Field{G1, G2, GT, Zr} {
+		a := pairing.NewUncheckedElement(field).RandFrom(rand.New(rand.NewSource(5)))
+		b := pairing.NewUncheckedElement(field).RandFrom(rand.New(rand.NewSource(5)))
+		if !a.Equals(b) {
+			t.Errorf("field %d: same reader produced different elements", field)
+		}
+		if field != Zr && !a.NewFieldElement().PowBig(a, pairing.Order()).Is1() {
+			t.Errorf("field %d: element is not in the subgroup of order r", field)
+		}
+	}
+
+	expectPanic(t, ErrEntropyFailure, func() { pairing.NewZr().RandFrom(failingReader{}) })
+}
@@ -0,0 +1,109 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// Fingerprint identifies a set of pairing parameters. It is the SHA-256 hash
+// of the parameters in the PBC text format.
+type Fingerprint [sha256.Size]byte
+
+// String returns the fingerprint in hexadecimal.
+func (fingerprint Fingerprint) String() string {
+	return hex.EncodeToString(fingerprint[:])
+}
+
+func fingerprintOf(params string) Fingerprint {
+	return Fingerprint(sha256.Sum256([]byte(params)))
+}
+
+// Fingerprint returns the fingerprint of the parameters.
+func (params *Params) Fingerprint() Fingerprint {
+	return fingerprintOf(params.String())
+}
+
+// Fingerprint returns the fingerprint of the parameters used to create the
+// pairing.
+func (pairing *Pairing) Fingerprint() Fingerprint {
+	return pairing.fingerprint
+}
+
+var registry = struct {
+	sync.RWMutex
+	pairings map[Fingerprint]*Pairing
+}{pairings: make(map[Fingerprint]*Pairing)}
+
+// RegisterPairing adds pairing to a process-wide registry and returns its
+// fingerprint. Marshaled elements carry the fingerprint of their pairing, so
+// registered pairings allow elements to be unmarshaled into the zero value of
+// Element (e.g., when decoding gob or JSON data into structs containing
+// *Element fields). If a pairing with the same parameters was previously
+// registered, it is replaced.
+//
+// The registry holds a reference to the pairing, preventing it from being
+// garbage collected until it is removed with UnregisterPairing.
+func RegisterPairing(pairing *Pairing) Fingerprint {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.pairings[pairing.fingerprint] = pairing
+	return pairing.fingerprint
+}
+
+// UnregisterPairing removes the pairing with the given fingerprint from the
+// registry. It does nothing if no such pairing is registered.
+func UnregisterPairing(fingerprint Fingerprint) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.pairings, fingerprint)
+}
+
+// LookupPairing returns the registered pairing with the given fingerprint.
+// The second return value is false if no such pairing is registered. Closing a
+// pairing removes it from the registry.
+func LookupPairing(fingerprint Fingerprint) (*Pairing, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	pairing, ok := registry.pairings[fingerprint]
+	if ok {
+		pairing.mu.Lock()
+		ok = !pairing.closed
+		pairing.mu.Unlock()
+	}
+	if !ok {
+		return nil, false
+	}
+	return pairing, true
+}
+
+// unregisterClosed removes pairing from the registry if it is registered. It
+// is called when the pairing is closed.
+func unregisterClosed(pairing *Pairing) {
+	registry.Lock()
+	defer registry.Unlock()
+	if registry.pairings[pairing.fingerprint] == pairing {
+		delete(registry.pairings, pairing.fingerprint)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+/*
+#include <pbc/pbc.h>
+
+// overwriteElement sets every base field coefficient of e to the largest
+// element of its field, so that all limbs of the coefficient are written with
+// a value that does not depend on the previous contents.
+static void overwriteElement(element_ptr e) {
+	int i, n = element_item_count(e);
+	if (n == 0) {
+		mpz_t t;
+		mpz_init(t);
+		mpz_sub_ui(t, e->field->order, 1);
+		element_set_mpz(e, t);
+		mpz_clear(t);
+		return;
+	}
+	for (i = 0; i < n; i++) overwriteElement(element_item(e, i));
+}
+
+void zeroizeElement(element_ptr e) {
+	overwriteElement(e);
+	element_set0(e);
+}
+
+// zeroizePoint is like zeroizeElement for points on a curve. PBC exposes no
+// coordinates for the point at infinity, even though the coordinates of an
+// earlier value remain in memory, so the point is first replaced by a fixed
+// finite point to overwrite them.
+void zeroizePoint(element_ptr e) {
+	unsigned char data = 0;
+	while (element_is0(e) && data < 255) {
+		element_from_hash(e, &data, 1);
+		data++;
+	}
+	zeroizeElement(e);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// Zeroize overwrites the value of el in native memory, and then sets el to
+// zero. It should be called on elements that hold secrets, such as private
+// keys, once they are no longer needed. PBC and GMP free memory without
+// clearing it, so without Zeroize, secrets may remain in freed memory
+// indefinitely.
+//
+// Each coefficient of el is first overwritten with a public value as wide as
+// the field, so that every limb that can hold a reduced value is written.
+// Zeroize cannot erase copies made elsewhere, such as big.Int values returned
+// by BigInt, byte slices returned by Bytes, or memory released by GMP when it
+// resized an integer. The temporary integers that this package creates for
+// exponents, such as in PowZnCT and MultiExpZn, are allocated at their final
+// size and wiped before they are freed; scratch space used inside GMP and PBC
+// is not.
+//
+// Zeroize does nothing if el has been closed.
+func (el *Element) Zeroize() {
+	if el.cptr == nil {
+		return
+	}
+	if el.field == G1 || el.field == G2 {
+		C.zeroizePoint(el.cptr)
+		return
+	}
+	C.zeroizeElement(el.cptr)
+}
+
+// SecretScalar holds a secret element of Zr, such as a private key. It is a
+// thin wrapper around an Element that prevents the secret from being leaked
+// accidentally: formatting a SecretScalar with the fmt package always produces
+// "[REDACTED]", and the secret is zeroized before its memory is freed. The
+// underlying element is only available through Reveal.
+type SecretScalar struct {
+	el *Element
+}
+
+const redacted = "[REDACTED]"
+
+// NewSecretScalar creates a new secret scalar in Zr for the pairing. Its
+// value is zero.
+func (pairing *Pairing) NewSecretScalar() *SecretScalar {
+	s := &SecretScalar{el: pairing.NewZr()}
+	runtime.SetFinalizer(s, (*SecretScalar).Close)
+	return s
+}
+
+// Set sets s to the value of x, which must be an element of Zr, and returns
+// s. The caller should zeroize x if it is no longer needed.
+func (s *SecretScalar) Set(x *Element) *SecretScalar {
+	s.el.Set(x)
+	return s
+}
+
+// Rand sets s to a random value using the global random source, and returns
+// s.
+func (s *SecretScalar) Rand() *SecretScalar {
+	s.el.Rand()
+	return s
+}
+
+// RandFromSource sets s to a uniformly random value drawn from src, and
+// returns s.
+func (s *SecretScalar) RandFromSource(src RandomSource) *SecretScalar {
+	s.el.RandFromSource(src)
+	return s
+}
+
+// RandFrom sets s to a uniformly random value using r as the entropy source,
+// and returns s.
+func (s *SecretScalar) RandFrom(r io.Reader) *SecretScalar {
+	s.el.RandFrom(r)
+	return s
+}
+
+// Reveal returns the element holding the secret, for use as an operand, for
+// example in PowZnCT. The element remains owned by s: it must not be closed or
+// retained after s is closed. Formatting or serializing the element exposes
+// the secret.
+func (s *SecretScalar) Reveal() *Element {
+	s.el.ensureOpen()
+	return s.el
+}
+
+// Zeroize sets s to zero, overwriting the secret in native memory. s remains
+// usable.
+func (s *SecretScalar) Zeroize() { s.el.Zeroize() }
+
+// Close zeroizes s and frees its native memory. Close is idempotent.
+func (s *SecretScalar) Close() {
+	runtime.SetFinalizer(s, nil)
+	s.el.Zeroize()
+	s.el.Close()
+}
+
+// String returns "[REDACTED]".
+func (s *SecretScalar) String() string { return redacted }
+
+// GoString returns "[REDACTED]".
+func (s *SecretScalar) GoString() string { return redacted }
+
+// Format writes "[REDACTED]" for every verb, so that the secret cannot be
+// printed accidentally. Use Reveal to format the secret intentionally.
+func (s *SecretScalar) Format(f fmt.State, c rune) {
+	io.WriteString(f, redacted)
+}
@@ -0,0 +1,88 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestZeroize(t *testing.T) {
+	pairing := testPairing(t)
+	for _, el := range []*Element{pairing.NewZr().Rand(), pairing.NewG1().Rand(), pairing.NewGT().Rand()} {
+		el.Zeroize()
+		if !el.Is0() {
+			t.Error("zeroized element is not zero")
+		}
+		el.Close()
+		el.Zeroize()
+	}
+}
+
+func TestZeroizeIdentity(t *testing.T) {
+	// PBC keeps the coordinates of a point when it is set to the identity,
+	// so they must still be overwritten
+	pairing := testPairing(t)
+	for _, el := range []*Element{pairing.NewG1().Set0(), pairing.NewG1().Rand().Set0(), pairing.NewG2().Rand().Set0()} {
+		el.Zeroize()
+		if !el.Is0() {
+			t.Error("zeroized identity is not the identity")
+		}
+		if el.Rand().Zeroize(); !el.Is0() {
+			t.Error("zeroized element could not be reused")
+		}
+	}
+}
+
+func TestSecretScalar(t *testing.T) {
+	pairing := testPairing(t)
+	s := pairing.NewSecretScalar().Rand()
+	for s.Reveal().Is0() {
+		s.Rand()
+	}
+	secret := s.Reveal().String()
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%d", "%x", "%q"} {
+		out := fmt.Sprintf(format, s)
+		if out != "[REDACTED]" || strings.Contains(out, secret) {
+			t.Errorf("%s formatted the secret as %q", format, out)
+		}
+	}
+	if s.String() != "[REDACTED]" {
+		t.Error("String revealed the secret")
+	}
+
+	g := pairing.NewG1().Rand()
+	expected := pairing.NewG1().PowZn(g, pairing.NewZr().Set(s.Reveal()))
+	if !pairing.NewG1().PowZnCT(g, s.Reveal()).Equals(expected) {
+		t.Error("revealed element has the wrong value")
+	}
+
+	s.Zeroize()
+	if !s.Reveal().Is0() {
+		t.Error("secret was not zeroized")
+	}
+	s.Close()
+	s.Close()
+	expectPanic(t, ErrClosed, func() { s.Reveal() })
+}
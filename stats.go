@@ -0,0 +1,211 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"sync/atomic"
+)
+
+// ObjectKind identifies a category of native objects allocated by this
+// package.
+type ObjectKind int
+
+const (
+	ElementObject ObjectKind = iota
+	PowerObject   ObjectKind = iota
+	PairerObject  ObjectKind = iota
+	MpzObject     ObjectKind = iota
+)
+
+var objectKindNames = [...]string{"element", "power", "pairer", "mpz"}
+
+// String returns the name of the kind of object.
+func (kind ObjectKind) String() string {
+	if kind < 0 || int(kind) >= len(objectKindNames) {
+		return "unknown"
+	}
+	return objectKindNames[kind]
+}
+
+// ObjectStats holds allocation statistics for one category of native objects.
+// Allocs and Frees count the objects allocated and freed since the program
+// started; Live is their difference. LiveBytes approximates the C heap memory
+// held by the live objects.
+type ObjectStats struct {
+	Allocs    int64
+	Frees     int64
+	Live      int64
+	LiveBytes int64
+}
+
+// MemStats reports the native objects allocated by this package. These objects
+// live on the C heap, so they are invisible to Go's memory profiler and to
+// runtime.MemStats. Counts are exact. Byte counts are approximate: they
+// include the C structures and the element or integer data at the time of
+// allocation, but not the pre-processing tables of powers and pairers, whose
+// layout is private to PBC, nor growth of integers after allocation.
+//
+// A steadily growing Live count for objects that the program no longer uses
+// indicates that they are waiting for the garbage collector to run their
+// finalizers; calling Close on them releases the memory promptly.
+type MemStats struct {
+	// Elements holds the statistics for elements of each Field, indexed by
+	// G1, G2, GT, and Zr.
+	Elements [Zr + 1]ObjectStats
+
+	// OtherElements holds the statistics for elements that do not belong to
+	// one of the pairing's groups, such as those created from coordinates
+	// using NewFieldElement.
+	OtherElements ObjectStats
+
+	Powers  ObjectStats
+	Pairers ObjectStats
+
+	// Mpz holds the statistics for the temporary GMP integers used to pass
+	// big.Int values to PBC.
+	Mpz ObjectStats
+}
+
+// LiveBytes returns the approximate total C heap memory held by live objects.
+func (stats *MemStats) LiveBytes() int64 {
+	total := stats.OtherElements.LiveBytes + stats.Powers.LiveBytes +
+		stats.Pairers.LiveBytes + stats.Mpz.LiveBytes
+	for _, s := range stats.Elements {
+		total += s.LiveBytes
+	}
+	return total
+}
+
+// AllocEvent describes the allocation or release of a native object. It is
+// passed to the function registered with SetAllocHook.
+type AllocEvent struct {
+	Kind ObjectKind
+
+	// Field is the field of an element, or -1 for elements created from
+	// coordinates and for other kinds of objects.
+	Field Field
+
+	// Bytes approximates the memory allocated or released, as in MemStats.
+	Bytes int64
+
+	// Free is true if the object was released rather than allocated.
+	Free bool
+}
+
+// statSlot indexes the counters in allocStats. The first slots correspond to
+// the fields G1, G2, GT, and Zr.
+type statSlot int
+
+const (
+	slotOtherElements statSlot = statSlot(Zr) + 1 + iota
+	slotPowers
+	slotPairers
+	slotMpz
+	numStatSlots
+)
+
+type statCounters struct {
+	allocs int64
+	frees  int64
+	bytes  int64
+}
+
+var allocStats [numStatSlots]statCounters
+
+var allocHook atomic.Value // holds a func(AllocEvent), possibly nil
+
+// Stats returns the current allocation statistics for native objects. The
+// statistics are maintained with atomic counters, so Stats may be called
+// concurrently with other operations; the counters of different categories
+// are not read as a single snapshot.
+func Stats() MemStats {
+	var stats MemStats
+	read := func(slot statSlot) ObjectStats {
+		c := &allocStats[slot]
+		s := ObjectStats{
+			Frees:     atomic.LoadInt64(&c.frees),
+			Allocs:    atomic.LoadInt64(&c.allocs),
+			LiveBytes: atomic.LoadInt64(&c.bytes),
+		}
+		s.Live = s.Allocs - s.Frees
+		return s
+	}
+	for field := range stats.Elements {
+		stats.Elements[field] = read(statSlot(field))
+	}
+	stats.OtherElements = read(slotOtherElements)
+	stats.Powers = read(slotPowers)
+	stats.Pairers = read(slotPairers)
+	stats.Mpz = read(slotMpz)
+	return stats
+}
+
+// SetAllocHook registers a function that is called whenever a native object
+// is allocated or released, or removes the hook if f is nil. This can be used
+// to feed the statistics into a metrics system or to trace leaks. The hook is
+// called synchronously, possibly from finalizers running on the garbage
+// collector's finalizer goroutine, so it must be fast, safe for concurrent use,
+// and must not use this package.
+//
+// Go's runtime/metrics package only reports metrics defined by the runtime
+// and cannot be extended, so native memory must be exported through the hook
+// or Stats instead.
+func SetAllocHook(f func(AllocEvent)) {
+	allocHook.Store(f)
+}
+
+func recordAlloc(kind ObjectKind, field Field, bytes int64) {
+	slot := kindSlot(kind, field)
+	atomic.AddInt64(&allocStats[slot].allocs, 1)
+	atomic.AddInt64(&allocStats[slot].bytes, bytes)
+	notifyAllocHook(AllocEvent{Kind: kind, Field: field, Bytes: bytes})
+}
+
+func recordFree(kind ObjectKind, field Field, bytes int64) {
+	slot := kindSlot(kind, field)
+	atomic.AddInt64(&allocStats[slot].frees, 1)
+	atomic.AddInt64(&allocStats[slot].bytes, -bytes)
+	notifyAllocHook(AllocEvent{Kind: kind, Field: field, Bytes: bytes, Free: true})
+}
+
+func kindSlot(kind ObjectKind, field Field) statSlot {
+	switch kind {
+	case ElementObject:
+		if field >= G1 && field <= Zr {
+			return statSlot(field)
+		}
+		return slotOtherElements
+	case PowerObject:
+		return slotPowers
+	case PairerObject:
+		return slotPairers
+	case MpzObject:
+		return slotMpz
+	}
+	panic(ErrInternal)
+}
+
+func notifyAllocHook(event AllocEvent) {
+	if f, _ := allocHook.Load().(func(AllocEvent)); f != nil {
+		f(event)
+	}
+}
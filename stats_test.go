@@ -0,0 +1,77 @@
+// Copyright © 2018 Nik Unger
+//
+// This file is part of The PBC Go Wrapper.
+//
+// The PBC Go Wrapper is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or (at your
+// option) any later version.
+//
+// The PBC Go Wrapper is distributed in the hope that it will be useful, but
+// WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+// or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser General Public
+// License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with The PBC Go Wrapper. If not, see <http://www.gnu.org/licenses/>.
+//
+// The PBC Go Wrapper makes use of The PBC library. The PBC Library and its use
+// are covered under the terms of the GNU Lesser General Public License
+// version 3, or (at your option) any later version.
+
+package pbc
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	pairing := testPairing(t)
+
+	var mu sync.Mutex
+	var events []AllocEvent
+	SetAllocHook(func(event AllocEvent) {
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+	})
+	defer SetAllocHook(nil)
+
+	before := Stats()
+	g := pairing.NewG1().Rand()
+	power := g.PreparePower()
+	during := Stats()
+	power.Close()
+	g.Close()
+	after := Stats()
+
+	if n := during.Elements[G1].Allocs - before.Elements[G1].Allocs; n != 1 {
+		t.Errorf("expected 1 G1 allocation, got %d", n)
+	}
+	if n := during.Powers.Allocs - before.Powers.Allocs; n != 1 {
+		t.Errorf("expected 1 power allocation, got %d", n)
+	}
+	if during.Elements[G1].LiveBytes <= 0 || during.LiveBytes() <= 0 {
+		t.Error("live bytes not recorded")
+	}
+	if n := after.Elements[G1].Frees - during.Elements[G1].Frees; n < 1 {
+		t.Error("closed element not recorded as freed")
+	}
+	if n := after.Powers.Frees - during.Powers.Frees; n < 1 {
+		t.Error("closed power not recorded as freed")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var allocated, freed bool
+	for _, event := range events {
+		if event.Kind == PowerObject {
+			allocated = allocated || !event.Free
+			freed = freed || event.Free
+		}
+	}
+	if !allocated || !freed {
+		t.Error("allocation hook was not called for the power")
+	}
+}
@@ -22,10 +22,11 @@
 package pbc
 
 /*
+#include <stdint.h>
 #include <pbc/pbc.h>
 
 void installRandomHook();
-void uninstallRandomHook();
+void setRandomHandle(uintptr_t handle);
 */
 import "C"
 
@@ -34,17 +35,30 @@ import (
 	"io"
 	"math/big"
 	"math/rand"
+	"runtime"
+	"runtime/cgo"
+	"sync"
+	"sync/atomic"
 	"unsafe"
 )
 
-var logging bool
+var (
+	loggingMu sync.Mutex
+	logging   bool
+)
 
 // Logging returns true if PBC will send status messages to stderr.
-func Logging() bool { return logging }
+func Logging() bool {
+	loggingMu.Lock()
+	defer loggingMu.Unlock()
+	return logging
+}
 
 // SetLogging enables or disables sending PBC status messages to stderr.
-// Messages are hidden by default.
+// Messages are hidden by default. SetLogging is safe for concurrent use.
 func SetLogging(log bool) {
+	loggingMu.Lock()
+	defer loggingMu.Unlock()
 	logging = log
 	if log {
 		C.pbc_set_msg_to_stderr(C.int(1))
@@ -54,36 +68,69 @@ func SetLogging(log bool) {
 }
 
 // RandomSource generates random numbers for consumption by PBC. Rand returns a
-// random integer in [0,limit).
+// random integer in [0,limit). A RandomSource installed with
+// SetRandomProvider may be called from several goroutines at once, so it must
+// be safe for concurrent use.
 type RandomSource interface {
 	Rand(limit *big.Int) *big.Int
 }
 
-var randomProvider RandomSource
+// randomProvider holds a randomProviderBox, so that the callback from C can
+// load it without locking. PBC's random number function is set once, in init,
+// to a hook that calls into Go; the hook selects the source for each draw, so
+// replacing the source never modifies PBC's global state.
+var randomProvider atomic.Value
+
+type randomProviderBox struct {
+	provider RandomSource
+}
 
-// RandomProvider returns the current random number source for use by PBC.
-func RandomProvider() RandomSource { return randomProvider }
+// defaultRandomSource is used when no source has been set.
+var defaultRandomSource RandomSource = readerProvider{cryptorand.Reader}
+
+// RandomProvider returns the current random number source for use by PBC. It
+// returns nil if the default source is in use.
+func RandomProvider() RandomSource {
+	box, _ := randomProvider.Load().(randomProviderBox)
+	return box.provider
+}
 
 // SetRandomProvider sets the random number source for use by PBC. If provider
-// is nil, then PBC will use its internal random number generator, which is the
-// default mode. If provider is non-nil, then requests for random numbers will
-// be serviced by Go instead of the internal C functions. This is slower, but
-// provides greater control. Several convenience functions are provided to set
-// common sources of random numbers.
+// is nil, then the default source, crypto/rand.Reader, is used. Several
+// convenience functions are provided to set common sources of random numbers.
+//
+// SetRandomProvider is safe for concurrent use. Operations that are running
+// while the source is replaced may draw some of their random numbers from the
+// previous source. See the package documentation for details.
 func SetRandomProvider(provider RandomSource) {
-	randomProvider = provider
-	if provider == nil {
-		C.uninstallRandomHook()
-	} else {
-		C.installRandomHook()
-	}
+	randomProvider.Store(randomProviderBox{provider})
+}
+
+// withRandomSource calls f with source installed as the random source for the
+// calling thread, so that PBC functions called by f draw from source instead
+// of the global source. source is passed to the hook by handle, and the
+// goroutine is locked to its thread while f runs.
+func withRandomSource(source RandomSource, f func()) {
+	handle := cgo.NewHandle(source)
+	defer handle.Delete()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.setRandomHandle(C.uintptr_t(handle))
+	defer C.setRandomHandle(0)
+	f()
 }
 
 //export goGenerateRandom
-func goGenerateRandom(out, limit unsafe.Pointer) {
+func goGenerateRandom(out, limit unsafe.Pointer, handle C.uintptr_t) {
 	outMpz := &mpz{i: *(**C.mpz_t)(out)}
 	limitMpz := &mpz{i: *(**C.mpz_t)(limit)}
-	r := randomProvider.Rand(mpz2big(limitMpz))
+	var provider RandomSource
+	if handle != 0 {
+		provider = cgo.Handle(handle).Value().(RandomSource)
+	} else if provider = RandomProvider(); provider == nil {
+		provider = defaultRandomSource
+	}
+	r := provider.Rand(mpz2big(limitMpz))
 	big2thisMpz(r, outMpz)
 }
 
@@ -100,39 +147,57 @@ func (provider readerProvider) Rand(limit *big.Int) (result *big.Int) {
 }
 
 type randProvider struct {
+	mu     sync.Mutex // rand.Rand is not safe for concurrent use
 	source *rand.Rand
 }
 
-func (provider randProvider) Rand(limit *big.Int) (result *big.Int) {
+func (provider *randProvider) Rand(limit *big.Int) (result *big.Int) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
 	result = &big.Int{}
 	result.Rand(provider.source, limit)
 	return
 }
 
+// NewReaderSource returns a RandomSource that uses the crypto/rand package to
+// generate random numbers using the given reader as an entropy source. The
+// source panics with ErrEntropyFailure if reading fails. It is safe for
+// concurrent use if the reader is.
+func NewReaderSource(reader io.Reader) RandomSource {
+	if reader == nil {
+		panic(ErrIllegalNil)
+	}
+	return readerProvider{reader}
+}
+
+// NewRandSource returns a RandomSource that draws random numbers from rand.
+// The source is safe for concurrent use. It is not cryptographically secure,
+// but is useful for reproducible tests.
+func NewRandSource(rand *rand.Rand) RandomSource {
+	if rand == nil {
+		panic(ErrIllegalNil)
+	}
+	return &randProvider{source: rand}
+}
+
 // SetCryptoRandom causes PBC to use the crypto/rand package with the globally
 // shared rand.Reader as the source of random numbers.
 func SetCryptoRandom() { SetReaderRandom(cryptorand.Reader) }
 
 // SetReaderRandom causes PBC to use the crypto/rand package to generate random
 // numbers using the given reader as an entropy source.
-func SetReaderRandom(reader io.Reader) {
-	if reader == nil {
-		panic(ErrIllegalNil)
-	}
-	SetRandomProvider(&readerProvider{reader})
-}
+func SetReaderRandom(reader io.Reader) { SetRandomProvider(NewReaderSource(reader)) }
 
 // SetRandRandom causes PBC to use the given source of random numbers.
-func SetRandRandom(rand *rand.Rand) { SetRandomProvider(&randProvider{rand}) }
+func SetRandRandom(rand *rand.Rand) { SetRandomProvider(NewRandSource(rand)) }
 
-// SetDefaultRandom causes PBC to use its internal source of random numbers.
-// This is the default mode of operation. Internally, PBC will attempt to read
-// from /dev/urandom if it exists, or from the Microsoft Crypto API on Windows.
-// If neither of these sources is available, the library will fall back to an
-// insecure PRNG.
+// SetDefaultRandom restores the default source of random numbers, which is the
+// globally shared crypto/rand.Reader. Unlike the internal source of PBC, it
+// never falls back to an insecure PRNG.
 func SetDefaultRandom() { SetRandomProvider(nil) }
 
 func init() {
 	SetLogging(false)
+	C.installRandomHook()
 	SetDefaultRandom()
 }